@@ -0,0 +1,139 @@
+package gocontainers
+
+import "fmt"
+
+// AggregateStack is a LIFO stack that answers Min, Max and a user-supplied
+// associative fold (sum, gcd, ...) over all of its elements in O(1). Each
+// entry records the aggregates of itself and everything beneath it, so Pop
+// restores the previous aggregates for free.
+type AggregateStack[T any] struct {
+	frames     []aggregateFrame[T]
+	comparator func(a, b T) bool
+	fold       func(acc, element T) T
+}
+
+type aggregateFrame[T any] struct {
+	element T
+	min     T
+	max     T
+	agg     T
+}
+
+// NewAggregateStack creates a new AggregateStack ordered by comparator,
+// which takes the same form as NewHeap's: it returns true if element a
+// comes before element b. Min is then the element a Heap with the same
+// comparator would pop first, so a min-heap comparator such as cmp.Less
+// gives the smallest element.
+func NewAggregateStack[T any](comparator func(a, b T) bool) *AggregateStack[T] {
+	return NewAggregateStackWithFold(comparator, nil)
+}
+
+// NewAggregateStackWithFold creates a new AggregateStack ordered by
+// comparator, as for NewAggregateStack, that also maintains fold over its
+// elements, from bottom to top. fold must be associative.
+func NewAggregateStackWithFold[T any](comparator func(a, b T) bool, fold func(acc, element T) T) *AggregateStack[T] {
+	return &AggregateStack[T]{frames: make([]aggregateFrame[T], 0), comparator: comparator, fold: fold}
+}
+
+func (s *AggregateStack[T]) Push(element T) {
+	frame := aggregateFrame[T]{element: element, min: element, max: element, agg: element}
+	if n := len(s.frames); n > 0 {
+		below := s.frames[n-1]
+		if s.comparator(below.min, element) {
+			frame.min = below.min
+		}
+		if s.comparator(element, below.max) {
+			frame.max = below.max
+		}
+		if s.fold != nil {
+			frame.agg = s.fold(below.agg, element)
+		}
+	}
+	s.frames = append(s.frames, frame)
+}
+
+func (s *AggregateStack[T]) Pop() T {
+	if len(s.frames) == 0 {
+		panic("Pop from empty stack")
+	}
+	lastIndex := len(s.frames) - 1
+	element := s.frames[lastIndex].element
+	s.frames = s.frames[:lastIndex]
+	return element
+}
+
+func (s *AggregateStack[T]) Peek() T {
+	return s.top("Peek").element
+}
+
+// Min returns the element comparator puts first.
+// It panics if the stack is empty.
+func (s *AggregateStack[T]) Min() T {
+	return s.top("Min").min
+}
+
+// Max returns the element comparator puts last.
+// It panics if the stack is empty.
+func (s *AggregateStack[T]) Max() T {
+	return s.top("Max").max
+}
+
+// Aggregate returns the fold over all elements in the stack. It panics if
+// the stack is empty or was created without a fold.
+func (s *AggregateStack[T]) Aggregate() T {
+	if s.fold == nil {
+		panic("Aggregate on stack without fold")
+	}
+	return s.top("Aggregate").agg
+}
+
+func (s *AggregateStack[T]) top(op string) aggregateFrame[T] {
+	if len(s.frames) == 0 {
+		panic(op + " from empty stack")
+	}
+	return s.frames[len(s.frames)-1]
+}
+
+func (s *AggregateStack[T]) Size() int {
+	return len(s.frames)
+}
+
+func (s *AggregateStack[T]) IsEmpty() bool {
+	return len(s.frames) == 0
+}
+
+func (s *AggregateStack[T]) Clear() {
+	s.frames = nil
+}
+
+// ToSlice returns the elements from bottom to top.
+func (s *AggregateStack[T]) ToSlice() []T {
+	result := make([]T, len(s.frames))
+	for i, frame := range s.frames {
+		result[i] = frame.element
+	}
+	return result
+}
+
+// CheckInvariants verifies that each frame's Min and Max agree with the
+// frames beneath it. The fold is not checked, since T need not be
+// comparable. It is meant for tests.
+func (s *AggregateStack[T]) CheckInvariants() error {
+	equiv := func(a, b T) bool { return !s.comparator(a, b) && !s.comparator(b, a) }
+	for i, frame := range s.frames {
+		lo, hi := frame.element, frame.element
+		if i > 0 {
+			below := s.frames[i-1]
+			if s.comparator(below.min, lo) {
+				lo = below.min
+			}
+			if s.comparator(hi, below.max) {
+				hi = below.max
+			}
+		}
+		if !equiv(frame.min, lo) || !equiv(frame.max, hi) {
+			return fmt.Errorf("AggregateStack: frame %d min or max disagrees with the frames below", i)
+		}
+	}
+	return nil
+}
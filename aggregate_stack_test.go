@@ -0,0 +1,183 @@
+package gocontainers
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func TestAggregateStackMinMax(t *testing.T) {
+	s := NewAggregateStack(intLess)
+	s.Push(5)
+	assert.Equal(t, 5, s.Min())
+	assert.Equal(t, 5, s.Max())
+
+	s.Push(3)
+	s.Push(8)
+	s.Push(3)
+	assert.Equal(t, 3, s.Min())
+	assert.Equal(t, 8, s.Max())
+	assert.Equal(t, 4, s.Size())
+
+	assert.Equal(t, 3, s.Pop())
+	assert.Equal(t, 3, s.Min())
+	assert.Equal(t, 8, s.Pop())
+	assert.Equal(t, 5, s.Max())
+	assert.Equal(t, 3, s.Pop())
+	assert.Equal(t, 5, s.Min())
+	assert.Equal(t, 5, s.Peek())
+}
+
+func TestAggregateStackFold(t *testing.T) {
+	sum := NewAggregateStackWithFold(intLess, func(acc, v int) int { return acc + v })
+	for _, v := range []int{1, 2, 3, 4} {
+		sum.Push(v)
+	}
+	assert.Equal(t, 10, sum.Aggregate())
+	sum.Pop()
+	assert.Equal(t, 6, sum.Aggregate())
+
+	g := NewAggregateStackWithFold(intLess, gcd)
+	g.Push(12)
+	g.Push(18)
+	assert.Equal(t, 6, g.Aggregate())
+	g.Push(8)
+	assert.Equal(t, 2, g.Aggregate())
+	g.Pop()
+	assert.Equal(t, 6, g.Aggregate())
+}
+
+func TestAggregateStackNonComparable(t *testing.T) {
+	type sample struct {
+		latency time.Duration
+		tags    []string
+	}
+	s := NewAggregateStack(func(a, b sample) bool { return a.latency < b.latency })
+	s.Push(sample{latency: 20 * time.Millisecond, tags: []string{"a"}})
+	s.Push(sample{latency: 5 * time.Millisecond})
+	s.Push(sample{latency: 50 * time.Millisecond})
+
+	assert.Equal(t, 5*time.Millisecond, s.Min().latency)
+	assert.Equal(t, 50*time.Millisecond, s.Max().latency)
+}
+
+func TestAggregateStackEmpty(t *testing.T) {
+	s := NewAggregateStackWithFold(intLess, func(acc, v int) int { return acc + v })
+	assert.True(t, s.IsEmpty())
+	assert.Panics(t, func() { s.Pop() })
+	assert.Panics(t, func() { s.Peek() })
+	assert.Panics(t, func() { s.Min() })
+	assert.Panics(t, func() { s.Max() })
+	assert.Panics(t, func() { s.Aggregate() })
+
+	s.Push(1)
+	s.Clear()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+}
+
+func TestAggregateStackWithoutFold(t *testing.T) {
+	s := NewAggregateStack(intLess)
+	s.Push(1)
+	assert.Panics(t, func() { s.Aggregate() })
+}
+
+func TestAggregateStackHeapComparator(t *testing.T) {
+	// a max-heap comparator puts the largest element first
+	higher := func(a, b int) bool { return a > b }
+	h := NewHeap(higher)
+	s := NewAggregateStack(higher)
+	for _, v := range []int{3, 1, 4, 1, 5} {
+		h.PushItem(NewItem(v))
+		s.Push(v)
+	}
+	top, _ := h.Peek()
+	assert.Equal(t, top.Get(), s.Min())
+	assert.Equal(t, 1, s.Max())
+}
+
+func FuzzAggregateStack(f *testing.F) {
+	f.Add([]byte{0, 9, 17, 2, 3, 26, 2})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := NewAggregateStackWithFold(intLess, func(acc, v int) int { return acc + v })
+		var model []int
+		for i, op := range ops {
+			v := int(op>>3) - 16
+			switch op % 4 {
+			case 0, 1:
+				s.Push(v)
+				model = append(model, v)
+			case 2:
+				if len(model) > 0 {
+					want := model[len(model)-1]
+					model = model[:len(model)-1]
+					if got := s.Pop(); got != want {
+						t.Fatalf("op %d: Pop = %d, want %d", i, got, want)
+					}
+				}
+			case 3:
+				if v%4 == 0 {
+					s.Clear()
+					model = nil
+				}
+			}
+
+			if err := s.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), s.Size())
+			if len(model) > 0 {
+				sum := 0
+				for _, v := range model {
+					sum += v
+				}
+				assert.Equal(t, slices.Min(model), s.Min(), "after op %d (%d)", i, op)
+				assert.Equal(t, slices.Max(model), s.Max(), "after op %d (%d)", i, op)
+				assert.Equal(t, sum, s.Aggregate(), "after op %d (%d)", i, op)
+			}
+		}
+	})
+}
+
+func benchAggregateStackPushPop[T cmp.Ordered](b *testing.B, values []T) {
+	for b.Loop() {
+		s := NewAggregateStack(cmp.Less[T])
+		for _, v := range values {
+			s.Push(v)
+		}
+		for !s.IsEmpty() {
+			s.Pop()
+		}
+	}
+}
+
+func benchAggregateStackIterate[T cmp.Ordered](b *testing.B, values []T) {
+	s := NewAggregateStack(cmp.Less[T])
+	for _, v := range values {
+		s.Push(v)
+	}
+	for b.Loop() {
+		for range s.frames {
+		}
+	}
+}
+
+func BenchmarkAggregateStackPushPop(b *testing.B) {
+	runBench(b, benchAggregateStackPushPop[int], benchAggregateStackPushPop[string])
+}
+
+func BenchmarkAggregateStackIterate(b *testing.B) {
+	runBench(b, benchAggregateStackIterate[int], benchAggregateStackIterate[string])
+}
@@ -0,0 +1,53 @@
+package gocontainers
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchSizes are the container sizes every container benchmark runs at.
+var benchSizes = []int{16, 1024, 65536}
+
+func benchInts(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = (i * 7919) % n
+	}
+	return values
+}
+
+func benchStrings(n int) []string {
+	values := make([]string, n)
+	for i, v := range benchInts(n) {
+		values[i] = fmt.Sprintf("key-%08d", v)
+	}
+	return values
+}
+
+// runBench runs a benchmark body for every element type and size, as
+// sub-benchmarks named <type>/n=<size>.
+func runBench(b *testing.B, ints func(*testing.B, []int), strings func(*testing.B, []string)) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("int/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			ints(b, benchInts(n))
+		})
+	}
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("string/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			strings(b, benchStrings(n))
+		})
+	}
+}
+
+// runBenchSizes is runBench for containers with a fixed element type. It
+// runs body for every size, as sub-benchmarks named n=<size>.
+func runBenchSizes(b *testing.B, body func(*testing.B, []int)) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			body(b, benchInts(n))
+		})
+	}
+}
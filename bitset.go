@@ -0,0 +1,252 @@
+package gocontainers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+// BitSet is a set of small non-negative integers stored as a bit vector. It
+// uses one bit per value up to the largest value added, which makes it far
+// smaller and faster than Set[uint] when the values are dense. It grows
+// automatically as larger values are added.
+//
+// BitSet mirrors the method names of Set; Count is a synonym for Size.
+type BitSet struct {
+	words []uint64
+}
+
+const wordBits = 64
+
+// NewBitSet creates an empty BitSet with room for values below n before it
+// needs to grow.
+func NewBitSet(n uint) *BitSet {
+	return &BitSet{words: make([]uint64, 0, wordsFor(n))}
+}
+
+// wordsFor returns the number of words needed to hold values below n.
+func wordsFor(n uint) int {
+	return int((n + wordBits - 1) / wordBits)
+}
+
+// grow makes sure word index w exists.
+func (b *BitSet) grow(w int) {
+	if w < len(b.words) {
+		return
+	}
+	if w < cap(b.words) {
+		b.words = b.words[:w+1]
+		return
+	}
+	words := make([]uint64, w+1, max(w+1, 2*cap(b.words)))
+	copy(words, b.words)
+	b.words = words
+}
+
+// trim drops trailing zero words so that equal sets have equal lengths.
+func (b *BitSet) trim() {
+	n := len(b.words)
+	for n > 0 && b.words[n-1] == 0 {
+		n--
+	}
+	b.words = b.words[:n]
+}
+
+// Add adds i to the set.
+func (b *BitSet) Add(i uint) {
+	w := int(i / wordBits)
+	b.grow(w)
+	b.words[w] |= 1 << (i % wordBits)
+}
+
+// Remove removes i from the set.
+func (b *BitSet) Remove(i uint) {
+	w := int(i / wordBits)
+	if w >= len(b.words) {
+		return
+	}
+	b.words[w] &^= 1 << (i % wordBits)
+	b.trim()
+}
+
+// Contains reports whether i is in the set.
+func (b *BitSet) Contains(i uint) bool {
+	w := int(i / wordBits)
+	return w < len(b.words) && b.words[w]&(1<<(i%wordBits)) != 0
+}
+
+// Flip adds i if it is absent and removes it if it is present.
+func (b *BitSet) Flip(i uint) {
+	w := int(i / wordBits)
+	b.grow(w)
+	b.words[w] ^= 1 << (i % wordBits)
+	b.trim()
+}
+
+// Count returns the number of values in the set.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, word := range b.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Size returns the number of values in the set. It is the same as Count.
+func (b *BitSet) Size() int {
+	return b.Count()
+}
+
+func (b *BitSet) IsEmpty() bool {
+	return len(b.words) == 0
+}
+
+// Clear removes every value but keeps the allocated storage.
+func (b *BitSet) Clear() {
+	clear(b.words)
+	b.words = b.words[:0]
+}
+
+// NextSet returns the smallest value in the set that is >= i. The boolean
+// is false if there is none.
+func (b *BitSet) NextSet(i uint) (uint, bool) {
+	w := int(i / wordBits)
+	if w >= len(b.words) {
+		return 0, false
+	}
+	word := b.words[w] >> (i % wordBits)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != 0 {
+			return uint(w)*wordBits + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+	return 0, false
+}
+
+// All returns an iterator over the values in ascending order.
+func (b *BitSet) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for w, word := range b.words {
+			for word != 0 {
+				i := uint(w)*wordBits + uint(bits.TrailingZeros64(word))
+				if !yield(i) {
+					return
+				}
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// ToSlice returns the values in ascending order.
+func (b *BitSet) ToSlice() []uint {
+	result := make([]uint, 0, b.Count())
+	for i := range b.All() {
+		result = append(result, i)
+	}
+	return result
+}
+
+// Clone returns an independent copy of the set.
+func (b *BitSet) Clone() *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitSet{words: words}
+}
+
+// Union returns a new set containing the values in either set.
+func (b *BitSet) Union(other *BitSet) *BitSet {
+	long, short := b.words, other.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for w, word := range short {
+		words[w] |= word
+	}
+	return &BitSet{words: words}
+}
+
+// Intersection returns a new set containing the values in both sets.
+func (b *BitSet) Intersection(other *BitSet) *BitSet {
+	words := make([]uint64, min(len(b.words), len(other.words)))
+	for w := range words {
+		words[w] = b.words[w] & other.words[w]
+	}
+	result := &BitSet{words: words}
+	result.trim()
+	return result
+}
+
+// Difference returns a new set containing the values in b that are not in
+// other.
+func (b *BitSet) Difference(other *BitSet) *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	for w := range min(len(words), len(other.words)) {
+		words[w] &^= other.words[w]
+	}
+	result := &BitSet{words: words}
+	result.trim()
+	return result
+}
+
+// SymmetricDifference returns a new set containing the values in exactly
+// one of the two sets.
+func (b *BitSet) SymmetricDifference(other *BitSet) *BitSet {
+	long, short := b.words, other.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for w, word := range short {
+		words[w] ^= word
+	}
+	result := &BitSet{words: words}
+	result.trim()
+	return result
+}
+
+// Equal reports whether both sets contain the same values.
+func (b *BitSet) Equal(other *BitSet) bool {
+	if len(b.words) != len(other.words) {
+		return false
+	}
+	for w, word := range b.words {
+		if other.words[w] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the set as its 64-bit words in little-endian order,
+// lowest values first. An empty set encodes as no bytes.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(b.words)*8)
+	for _, word := range b.words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the contents of the set with data produced by
+// MarshalBinary.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return fmt.Errorf("BitSet: %w: length %d is not a multiple of 8", ErrInvalidEncoding, len(data))
+	}
+	words := make([]uint64, len(data)/8)
+	for w := range words {
+		words[w] = binary.LittleEndian.Uint64(data[w*8:])
+	}
+	b.words = words
+	b.trim()
+	return nil
+}
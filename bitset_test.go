@@ -0,0 +1,256 @@
+package gocontainers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBitSet(values ...uint) *BitSet {
+	b := NewBitSet(0)
+	for _, v := range values {
+		b.Add(v)
+	}
+	return b
+}
+
+func TestBitSetAddRemove(t *testing.T) {
+	b := NewBitSet(100)
+	assert.True(t, b.IsEmpty())
+
+	b.Add(3)
+	b.Add(64)
+	b.Add(1000)
+	b.Add(3)
+	assert.True(t, b.Contains(3))
+	assert.True(t, b.Contains(64))
+	assert.True(t, b.Contains(1000))
+	assert.False(t, b.Contains(4))
+	assert.False(t, b.Contains(1<<40))
+	assert.Equal(t, 3, b.Count())
+	assert.Equal(t, 3, b.Size())
+
+	b.Remove(1000)
+	b.Remove(5000)
+	assert.False(t, b.Contains(1000))
+	assert.Equal(t, []uint{3, 64}, b.ToSlice())
+
+	b.Clear()
+	assert.True(t, b.IsEmpty())
+	assert.False(t, b.Contains(3))
+}
+
+func TestBitSetFlip(t *testing.T) {
+	b := newBitSet(1)
+	b.Flip(1)
+	b.Flip(200)
+	assert.False(t, b.Contains(1))
+	assert.True(t, b.Contains(200))
+	b.Flip(200)
+	assert.True(t, b.IsEmpty())
+}
+
+func TestBitSetNextSet(t *testing.T) {
+	b := newBitSet(0, 5, 63, 64, 300)
+
+	var got []uint
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		got = append(got, i)
+	}
+	assert.Equal(t, []uint{0, 5, 63, 64, 300}, got)
+
+	i, ok := b.NextSet(65)
+	assert.True(t, ok)
+	assert.Equal(t, uint(300), i)
+	_, ok = b.NextSet(301)
+	assert.False(t, ok)
+	_, ok = NewBitSet(0).NextSet(0)
+	assert.False(t, ok)
+}
+
+func TestBitSetAll(t *testing.T) {
+	b := newBitSet(200, 7, 65)
+	assert.Equal(t, []uint{7, 65, 200}, slices.Collect(b.All()))
+
+	var first []uint
+	for i := range b.All() {
+		first = append(first, i)
+		break
+	}
+	assert.Equal(t, []uint{7}, first)
+}
+
+func TestBitSetOperations(t *testing.T) {
+	a := newBitSet(1, 2, 3, 100)
+	b := newBitSet(2, 3, 4, 500)
+
+	tests := []struct {
+		name string
+		got  *BitSet
+		want []uint
+	}{
+		{"Union", a.Union(b), []uint{1, 2, 3, 4, 100, 500}},
+		{"Intersection", a.Intersection(b), []uint{2, 3}},
+		{"Difference", a.Difference(b), []uint{1, 100}},
+		{"ReverseDifference", b.Difference(a), []uint{4, 500}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []uint{1, 4, 100, 500}},
+		{"SelfSymmetricDifference", a.SymmetricDifference(a), []uint{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got.ToSlice())
+			assert.True(t, tt.got.Equal(newBitSet(tt.want...)))
+		})
+	}
+
+	// operands are unchanged
+	assert.Equal(t, []uint{1, 2, 3, 100}, a.ToSlice())
+	assert.Equal(t, []uint{2, 3, 4, 500}, b.ToSlice())
+}
+
+func TestBitSetEqual(t *testing.T) {
+	a := newBitSet(1, 1000)
+	b := newBitSet(1, 1000)
+	assert.True(t, a.Equal(b))
+
+	// removing the high value must shrink a so it equals a set that never had it
+	a.Remove(1000)
+	assert.True(t, a.Equal(newBitSet(1)))
+	assert.False(t, a.Equal(b))
+}
+
+func TestBitSetClone(t *testing.T) {
+	a := newBitSet(1, 2)
+	c := a.Clone()
+	c.Add(3)
+	assert.False(t, a.Contains(3))
+	assert.True(t, c.Contains(3))
+}
+
+func TestBitSetBinary(t *testing.T) {
+	a := newBitSet(0, 63, 64, 1000)
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, 16*8)
+
+	var b BitSet
+	require.NoError(t, b.UnmarshalBinary(data))
+	assert.True(t, a.Equal(&b))
+
+	empty, err := NewBitSet(64).MarshalBinary()
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	// trailing zero words are accepted
+	require.NoError(t, b.UnmarshalBinary(make([]byte, 16)))
+	assert.True(t, b.IsEmpty())
+
+	err = b.UnmarshalBinary([]byte{1, 2, 3})
+	assert.True(t, errors.Is(err, ErrInvalidEncoding))
+}
+
+// FuzzBitSet applies a random sequence of operations, two bytes each, to a
+// BitSet and a map model and checks that they agree after every step.
+func FuzzBitSet(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 200, 1, 1, 2, 200, 3, 0})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		b := NewBitSet(0)
+		model := map[uint]bool{}
+		for i := 0; i+1 < len(ops); i += 2 {
+			v := uint(ops[i+1]) * 3
+			switch ops[i] % 4 {
+			case 0:
+				b.Add(v)
+				model[v] = true
+			case 1:
+				b.Remove(v)
+				delete(model, v)
+			case 2:
+				b.Flip(v)
+				if model[v] {
+					delete(model, v)
+				} else {
+					model[v] = true
+				}
+			case 3:
+				if v%4 == 0 {
+					b.Clear()
+					clear(model)
+				}
+			}
+
+			want := make([]uint, 0, len(model))
+			for v := range model {
+				want = append(want, v)
+			}
+			slices.Sort(want)
+			if got := b.ToSlice(); !slices.Equal(got, want) {
+				t.Fatalf("op %d: ToSlice = %v, want %v", i/2, got, want)
+			}
+			if b.Size() != len(model) || b.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Size = %d, want %d", i/2, b.Size(), len(model))
+			}
+			if b.Contains(v) != model[v] {
+				t.Fatalf("op %d: Contains(%d) = %v, want %v", i/2, v, b.Contains(v), model[v])
+			}
+		}
+	})
+}
+
+// BenchmarkBitSetContains compares BitSet with the map-backed Set on a
+// dense range of values.
+func BenchmarkBitSetContains(b *testing.B) {
+	for _, n := range benchSizes {
+		bits := NewBitSet(uint(n))
+		set := NewSet[uint]()
+		for i := 0; i < n; i += 2 {
+			bits.Add(uint(i))
+			set.Add(uint(i))
+		}
+		b.Run(fmt.Sprintf("BitSet/n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for i := range n {
+					_ = bits.Contains(uint(i))
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Set/n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for i := range n {
+					_ = set.Contains(uint(i))
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBitSetAddRemove(b *testing.B) {
+	runBenchSizes(b, func(b *testing.B, values []int) {
+		for b.Loop() {
+			s := NewBitSet(uint(len(values)))
+			for _, v := range values {
+				s.Add(uint(v))
+			}
+			for _, v := range values {
+				s.Remove(uint(v))
+			}
+		}
+	})
+}
+
+func BenchmarkBitSetIterate(b *testing.B) {
+	runBenchSizes(b, func(b *testing.B, values []int) {
+		s := NewBitSet(uint(len(values)))
+		for _, v := range values {
+			s.Add(uint(v))
+		}
+		for b.Loop() {
+			for range s.All() {
+			}
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package gocontainers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// BloomFilter is a probabilistic set. MayContain never returns false for a
+// value that was added, but may return true for a value that was not, at
+// roughly the false-positive rate the filter was sized for. It takes a few
+// bits per value regardless of the size of T, and values cannot be removed;
+// see CountingBloomFilter for that.
+//
+// Filters that are combined with Union or exchanged with MarshalBinary must
+// use the same Hasher; see Hasher.
+type BloomFilter[T comparable] struct {
+	bits *BitSet
+	m    uint64 // number of bits
+	k    int    // number of hash functions
+	hash Hasher[T]
+	id   uint64 // hasherID of hash
+}
+
+// NewBloomFilter returns a BloomFilter sized to hold n values with a
+// false-positive rate of p, hashed with hash/maphash and the process-wide
+// default seed.
+func NewBloomFilter[T comparable](n int, p float64) *BloomFilter[T] {
+	return NewBloomFilterWithHasher(n, p, defaultHasher[T]())
+}
+
+// NewBloomFilterWithHasher is like NewBloomFilter but uses hash.
+func NewBloomFilterWithHasher[T comparable](n int, p float64, hash Hasher[T]) *BloomFilter[T] {
+	m, k := bloomParams(n, p, "BloomFilter")
+	return &BloomFilter[T]{bits: NewBitSet(uint(m)), m: m, k: k, hash: hash, id: hasherID(hash)}
+}
+
+// bloomParams returns the optimal number of bits and hash functions for n
+// values at false-positive rate p.
+func bloomParams(n int, p float64, name string) (m uint64, k int) {
+	if n <= 0 {
+		panic(name + " expected count must be positive")
+	}
+	if !(p > 0 && p < 1) {
+		panic(name + " false-positive rate must be between 0 and 1")
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = max(1, int(math.Round(float64(m)/float64(n)*math.Ln2)))
+	return m, k
+}
+
+// bloomIndex returns the i-th of the positions for a value with hash h in a
+// filter of m slots. The positions come from double hashing, combining the
+// two halves of h, so the value is hashed only once.
+func bloomIndex(h uint64, i int, m uint64) uint64 {
+	h2 := bits.RotateLeft64(h, 32) | 1
+	return (h + uint64(i)*h2) % m
+}
+
+// bloomEstimate estimates the number of values added to a filter with m
+// slots and k hash functions of which set are in use.
+func bloomEstimate(set, m uint64, k int) float64 {
+	return -float64(m) / float64(k) * math.Log1p(-float64(set)/float64(m))
+}
+
+// Add adds v to the filter.
+func (f *BloomFilter[T]) Add(v T) {
+	h := f.hash(v)
+	for i := range f.k {
+		f.bits.Add(uint(bloomIndex(h, i, f.m)))
+	}
+}
+
+// MayContain reports whether v may have been added. A false result means v
+// was definitely not added.
+func (f *BloomFilter[T]) MayContain(v T) bool {
+	h := f.hash(v)
+	for i := range f.k {
+		if !f.bits.Contains(uint(bloomIndex(h, i, f.m))) {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimatedSize estimates the number of distinct values added from the
+// number of bits set. It is +Inf once every bit is set.
+func (f *BloomFilter[T]) EstimatedSize() float64 {
+	return bloomEstimate(uint64(f.bits.Count()), f.m, f.k)
+}
+
+func (f *BloomFilter[T]) IsEmpty() bool {
+	return f.bits.IsEmpty()
+}
+
+func (f *BloomFilter[T]) Clear() {
+	f.bits.Clear()
+}
+
+// Union returns a filter that may contain every value either filter may
+// contain. It returns ErrIncompatible if the filters were sized or hashed
+// differently.
+func (f *BloomFilter[T]) Union(other *BloomFilter[T]) (*BloomFilter[T], error) {
+	if f.m != other.m || f.k != other.k {
+		return nil, fmt.Errorf("BloomFilter: %w: %d bits and %d hashes vs %d bits and %d hashes",
+			ErrIncompatible, f.m, f.k, other.m, other.k)
+	}
+	if f.id != other.id {
+		return nil, fmt.Errorf("BloomFilter: %w: different hashers", ErrIncompatible)
+	}
+	return &BloomFilter[T]{bits: f.bits.Union(other.bits), m: f.m, k: f.k, hash: f.hash, id: f.id}, nil
+}
+
+// bloomHeader is the encoded size of k, m and the hasher fingerprint.
+const bloomHeader = 20
+
+// MarshalBinary encodes the filter's size, a fingerprint of its hasher and
+// its bits.
+func (f *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	n := wordsFor(uint(f.m))
+	data := make([]byte, 0, bloomHeader+8*n)
+	data = binary.LittleEndian.AppendUint32(data, uint32(f.k))
+	data = binary.LittleEndian.AppendUint64(data, f.m)
+	data = binary.LittleEndian.AppendUint64(data, f.id)
+	for w := range n {
+		var word uint64
+		if w < len(f.bits.words) {
+			word = f.bits.words[w]
+		}
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the size and contents of the filter with data
+// produced by MarshalBinary. The filter keeps its own hasher, and it
+// returns ErrIncompatible if the data was built with a different one.
+func (f *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if f.hash == nil {
+		return fmt.Errorf("BloomFilter: %w", ErrNoHasher)
+	}
+	if len(data) < bloomHeader {
+		return fmt.Errorf("BloomFilter: %w: truncated header", ErrInvalidEncoding)
+	}
+	k := binary.LittleEndian.Uint32(data)
+	m := binary.LittleEndian.Uint64(data[4:])
+	if binary.LittleEndian.Uint64(data[12:]) != f.id {
+		return fmt.Errorf("BloomFilter: %w: data was built with a different hasher", ErrIncompatible)
+	}
+	// the payload must hold exactly the words for m bits; comparing word
+	// counts keeps a huge m from overflowing
+	words := m / wordBits
+	if m%wordBits != 0 {
+		words++
+	}
+	payload := len(data) - bloomHeader
+	if k == 0 || m == 0 || k > 1<<16 || payload%8 != 0 || words != uint64(payload)/8 {
+		return fmt.Errorf("BloomFilter: %w: %d bits and %d hashes in %d bytes", ErrInvalidEncoding, m, k, len(data))
+	}
+	bits := NewBitSet(uint(m))
+	bits.words = bits.words[:wordsFor(uint(m))]
+	for w := range bits.words {
+		bits.words[w] = binary.LittleEndian.Uint64(data[bloomHeader+8*w:])
+	}
+	bits.trim()
+	if _, beyond := bits.NextSet(uint(m)); beyond {
+		return fmt.Errorf("BloomFilter: %w: bit set beyond size %d", ErrInvalidEncoding, m)
+	}
+	f.bits, f.m, f.k = bits, m, int(k)
+	return nil
+}
@@ -0,0 +1,198 @@
+package gocontainers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomParams(t *testing.T) {
+	m, k := bloomParams(1000, 0.01, "test")
+	assert.Equal(t, uint64(9586), m)
+	assert.Equal(t, 7, k)
+
+	assert.Panics(t, func() { bloomParams(0, 0.01, "test") })
+	assert.Panics(t, func() { bloomParams(10, 0, "test") })
+	assert.Panics(t, func() { bloomParams(10, 1, "test") })
+	assert.Panics(t, func() { bloomParams(10, math.NaN(), "test") })
+}
+
+func TestBloomFilterNoFalseNegatives(t *testing.T) {
+	f := NewBloomFilter[string](100, 0.01)
+	assert.True(t, f.IsEmpty())
+	assert.False(t, f.MayContain("a"))
+
+	words := []string{"alpha", "beta", "gamma", "delta"}
+	for _, w := range words {
+		f.Add(w)
+	}
+	for _, w := range words {
+		assert.True(t, f.MayContain(w), w)
+	}
+	assert.False(t, f.IsEmpty())
+
+	f.Clear()
+	assert.True(t, f.IsEmpty())
+	assert.False(t, f.MayContain("alpha"))
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, p = 10000, 0.01
+	f := NewBloomFilterWithHasher(n, p, mixHasher)
+	for i := range n {
+		f.Add(i)
+	}
+
+	falsePositives := 0
+	for i := n; i < 11*n; i++ {
+		if f.MayContain(i) {
+			falsePositives++
+		}
+	}
+	rate := float64(falsePositives) / (10 * n)
+	assert.Less(t, rate, 1.5*p)
+	assert.InDelta(t, n, f.EstimatedSize(), 0.05*n)
+}
+
+func TestBloomFilterUnion(t *testing.T) {
+	a := NewBloomFilterWithHasher(1000, 0.01, mixHasher)
+	b := NewBloomFilterWithHasher(1000, 0.01, mixHasher)
+	for i := range 500 {
+		a.Add(i)
+		b.Add(i + 500)
+	}
+
+	before := a.bits.Count()
+	u, err := a.Union(b)
+	require.NoError(t, err)
+	for i := range 1000 {
+		assert.True(t, u.MayContain(i))
+	}
+	assert.InDelta(t, 1000, u.EstimatedSize(), 50)
+	assert.Equal(t, before, a.bits.Count(), "operands are unchanged")
+
+	_, err = a.Union(NewBloomFilterWithHasher(1000, 0.1, mixHasher))
+	assert.True(t, errors.Is(err, ErrIncompatible))
+}
+
+func TestBloomFilterBinary(t *testing.T) {
+	f := NewBloomFilterWithHasher(100, 0.01, mixHasher)
+	for i := range 50 {
+		f.Add(i * 7)
+	}
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, bloomHeader+8*wordsFor(uint(f.m)))
+
+	g := NewBloomFilterWithHasher(1, 0.5, mixHasher)
+	require.NoError(t, g.UnmarshalBinary(data))
+	assert.Equal(t, f.m, g.m)
+	assert.Equal(t, f.k, g.k)
+	for i := range 50 {
+		assert.True(t, g.MayContain(i*7))
+	}
+	assert.Equal(t, f.EstimatedSize(), g.EstimatedSize())
+
+	// a bit beyond the recorded size
+	bad := append([]byte(nil), data...)
+	bad[4] = 1
+	clear(bad[5:12])
+	assert.True(t, errors.Is(g.UnmarshalBinary(bad[:bloomHeader+8]), ErrInvalidEncoding))
+
+	// a size the payload does not describe, which must not be allocated
+	huge := binary.LittleEndian.AppendUint32(nil, 3)
+	huge = binary.LittleEndian.AppendUint64(huge, 1<<62)
+	huge = binary.LittleEndian.AppendUint64(huge, g.id)
+	assert.True(t, errors.Is(g.UnmarshalBinary(huge), ErrInvalidEncoding))
+	binary.LittleEndian.PutUint64(huge[4:], 1<<36)
+	assert.True(t, errors.Is(g.UnmarshalBinary(append(huge, data[bloomHeader:]...)), ErrInvalidEncoding))
+	g.Add(1)
+}
+
+func TestBloomFilterHasherMismatch(t *testing.T) {
+	// filters built by the plain constructor share a hasher
+	a := NewBloomFilter[int](1000, 0.01)
+	b := NewBloomFilter[int](1000, 0.01)
+	for i := range 1000 {
+		a.Add(i)
+	}
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, b.UnmarshalBinary(data))
+	for i := range 1000 {
+		require.True(t, b.MayContain(i))
+	}
+	u, err := a.Union(b)
+	require.NoError(t, err)
+	assert.True(t, u.MayContain(999))
+
+	c := NewBloomFilterWithHasher(1000, 0.01, NewMaphashHasher[int]())
+	_, err = a.Union(c)
+	assert.True(t, errors.Is(err, ErrIncompatible))
+	assert.True(t, errors.Is(c.UnmarshalBinary(data), ErrIncompatible))
+	assert.True(t, c.IsEmpty(), "a rejected encoding leaves the filter unchanged")
+}
+
+// FuzzBloomFilterUnmarshal checks that UnmarshalBinary rejects malformed
+// input with ErrInvalidEncoding, or ErrIncompatible for another hasher, and that accepted input is exactly what
+// MarshalBinary produces for the decoded filter.
+func FuzzBloomFilterUnmarshal(f *testing.F) {
+	for _, n := range []int{1, 100} {
+		filter := NewBloomFilterWithHasher(n, 0.01, mixHasher)
+		for i := range n / 2 {
+			filter.Add(i)
+		}
+		data, _ := filter.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := NewBloomFilterWithHasher(1, 0.5, mixHasher)
+		if err := g.UnmarshalBinary(data); err != nil {
+			if !errors.Is(err, ErrInvalidEncoding) && !errors.Is(err, ErrIncompatible) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+		encoded, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded, data) {
+			t.Fatal("round trip changed the encoding")
+		}
+	})
+}
+
+func benchBloomFilterAdd[T comparable](b *testing.B, values []T) {
+	f := NewBloomFilter[T](len(values), 0.01)
+	for b.Loop() {
+		for _, v := range values {
+			f.Add(v)
+		}
+	}
+}
+
+func benchBloomFilterContains[T comparable](b *testing.B, values []T) {
+	f := NewBloomFilter[T](len(values), 0.01)
+	for _, v := range values {
+		f.Add(v)
+	}
+	for b.Loop() {
+		for _, v := range values {
+			f.MayContain(v)
+		}
+	}
+}
+
+func BenchmarkBloomFilterAdd(b *testing.B) {
+	runBench(b, benchBloomFilterAdd[int], benchBloomFilterAdd[string])
+}
+
+func BenchmarkBloomFilterContains(b *testing.B) {
+	runBench(b, benchBloomFilterContains[int], benchBloomFilterContains[string])
+}
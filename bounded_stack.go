@@ -0,0 +1,141 @@
+package gocontainers
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BoundedStack is a LIFO stack with a fixed capacity. What Push does when
+// the stack is full depends on its OverflowPolicy. A BoundedStack is safe
+// for concurrent use.
+type BoundedStack[T any] struct {
+	mu        sync.Mutex
+	notFull   *sync.Cond
+	stack     *Stack[T]
+	capacity  int
+	policy    OverflowPolicy
+	highWater int
+}
+
+// NewBoundedStack creates a new BoundedStack holding at most capacity
+// elements. policy decides what Push does when the stack is full:
+// OverflowReject, OverflowDropOldest or OverflowBlock.
+func NewBoundedStack[T any](capacity int, policy OverflowPolicy) *BoundedStack[T] {
+	if capacity <= 0 {
+		panic("BoundedStack capacity must be positive")
+	}
+	if policy != OverflowReject && policy != OverflowDropOldest && policy != OverflowBlock {
+		panic("unsupported overflow policy for BoundedStack")
+	}
+	s := &BoundedStack[T]{stack: NewStack[T](), capacity: capacity, policy: policy}
+	s.notFull = sync.NewCond(&s.mu)
+	return s
+}
+
+// Push adds element to the top of the stack. When the stack is full, Push
+// returns ErrFull (OverflowReject), discards the bottom element
+// (OverflowDropOldest), or waits for a Pop (OverflowBlock).
+func (s *BoundedStack[T]) Push(element T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.stack.Size() >= s.capacity {
+		switch s.policy {
+		case OverflowReject:
+			return ErrFull
+		case OverflowDropOldest:
+			s.stack.RemoveBottom()
+		default:
+			s.notFull.Wait()
+		}
+	}
+	s.stack.Push(element)
+	s.highWater = max(s.highWater, s.stack.Size())
+	return nil
+}
+
+func (s *BoundedStack[T]) Pop() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element := s.stack.Pop()
+	s.notFull.Signal()
+	return element
+}
+
+// TryPop removes and returns the element at the top of the stack.
+// It returns false if the stack is empty.
+func (s *BoundedStack[T]) TryPop() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stack.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	element := s.stack.Pop()
+	s.notFull.Signal()
+	return element, true
+}
+
+func (s *BoundedStack[T]) Peek() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.Peek()
+}
+
+func (s *BoundedStack[T]) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.Size()
+}
+
+func (s *BoundedStack[T]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.IsEmpty()
+}
+
+func (s *BoundedStack[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stack.Clear()
+	s.notFull.Broadcast()
+}
+
+// ToSlice returns the elements from bottom to top.
+func (s *BoundedStack[T]) ToSlice() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.ToSlice()
+}
+
+// Cap returns the maximum number of elements the stack can hold.
+func (s *BoundedStack[T]) Cap() int {
+	return s.capacity
+}
+
+// Remaining returns how many more elements can be pushed before the stack
+// is full.
+func (s *BoundedStack[T]) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.capacity - s.stack.Size()
+}
+
+// HighWaterMark returns the largest size the stack has reached.
+func (s *BoundedStack[T]) HighWaterMark() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.highWater
+}
+
+// CheckInvariants verifies that the stack is within its capacity and that
+// the high-water mark covers the current size. It is meant for tests.
+func (s *BoundedStack[T]) CheckInvariants() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := s.stack.Size(); n > s.capacity {
+		return fmt.Errorf("BoundedStack: size %d exceeds capacity %d", n, s.capacity)
+	} else if s.highWater < n || s.highWater > s.capacity {
+		return fmt.Errorf("BoundedStack: high-water mark %d with size %d and capacity %d", s.highWater, n, s.capacity)
+	}
+	return nil
+}
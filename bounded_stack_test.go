@@ -0,0 +1,205 @@
+package gocontainers
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedStackReject(t *testing.T) {
+	s := NewBoundedStack[int](2, OverflowReject)
+	assert.Equal(t, 2, s.Cap())
+	assert.Equal(t, 2, s.Remaining())
+
+	assert.NoError(t, s.Push(1))
+	assert.NoError(t, s.Push(2))
+	assert.Equal(t, 0, s.Remaining())
+	assert.ErrorIs(t, s.Push(3), ErrFull)
+
+	assert.Equal(t, 2, s.Pop())
+	assert.Equal(t, 1, s.Remaining())
+	assert.NoError(t, s.Push(3))
+	assert.Equal(t, 3, s.Peek())
+}
+
+func TestBoundedStackDropOldest(t *testing.T) {
+	s := NewBoundedStack[int](3, OverflowDropOldest)
+	for i := 1; i <= 5; i++ {
+		assert.NoError(t, s.Push(i))
+	}
+	assert.Equal(t, 3, s.Size())
+	assert.Equal(t, 5, s.Pop())
+	assert.Equal(t, 4, s.Pop())
+	assert.Equal(t, 3, s.Pop())
+	assert.True(t, s.IsEmpty())
+}
+
+func TestBoundedStackBlock(t *testing.T) {
+	s := NewBoundedStack[int](1, OverflowBlock)
+	assert.NoError(t, s.Push(1))
+
+	pushed := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Push(2))
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("Push should block while the stack is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	assert.Equal(t, 1, s.Pop())
+	<-pushed
+	assert.Equal(t, 2, s.Peek())
+}
+
+func TestBoundedStackClearWakesBlockedPush(t *testing.T) {
+	s := NewBoundedStack[int](1, OverflowBlock)
+	assert.NoError(t, s.Push(1))
+
+	pushed := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Push(2))
+		close(pushed)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	s.Clear()
+	<-pushed
+	assert.Equal(t, 1, s.Size())
+}
+
+func TestBoundedStackHighWaterMark(t *testing.T) {
+	s := NewBoundedStack[int](10, OverflowReject)
+	assert.Equal(t, 0, s.HighWaterMark())
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, s.Push(i))
+	}
+	s.Pop()
+	s.Pop()
+	assert.NoError(t, s.Push(9))
+	assert.Equal(t, 4, s.HighWaterMark())
+	s.Clear()
+	assert.Equal(t, 4, s.HighWaterMark())
+}
+
+func TestBoundedStackEmpty(t *testing.T) {
+	s := NewBoundedStack[int](1, OverflowReject)
+	_, ok := s.TryPop()
+	assert.False(t, ok)
+	assert.Panics(t, func() { s.Pop() })
+	assert.Panics(t, func() { s.Peek() })
+}
+
+func TestBoundedStackInvalidArgs(t *testing.T) {
+	assert.Panics(t, func() { NewBoundedStack[int](0, OverflowReject) })
+	assert.Panics(t, func() { NewBoundedStack[int](1, OverflowDropLowest) })
+}
+
+func TestBoundedStackConcurrent(t *testing.T) {
+	const workers, perWorker = 4, 1000
+	s := NewBoundedStack[int](8, OverflowBlock)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				assert.NoError(t, s.Push(i))
+			}
+		}()
+	}
+
+	popped := 0
+	for popped < workers*perWorker {
+		if _, ok := s.TryPop(); ok {
+			popped++
+		} else {
+			runtime.Gosched()
+		}
+	}
+	wg.Wait()
+	assert.True(t, s.IsEmpty())
+	assert.LessOrEqual(t, s.HighWaterMark(), 8)
+}
+
+func FuzzBoundedStack(f *testing.F) {
+	f.Add(uint8(3), false, []byte{0, 8, 16, 24, 1, 2, 32})
+	f.Add(uint8(2), true, []byte{0, 8, 16, 24, 1, 2, 32})
+	f.Fuzz(func(t *testing.T, capacity uint8, dropOldest bool, ops []byte) {
+		policy := OverflowReject
+		if dropOldest {
+			policy = OverflowDropOldest
+		}
+		s := NewBoundedStack[int](int(capacity%8)+1, policy)
+		var model []int
+		highWater := 0
+		for i, op := range ops {
+			v := int(op >> 3)
+			switch op % 4 {
+			case 0, 1:
+				err := s.Push(v)
+				switch {
+				case len(model) < s.Cap():
+					model = append(model, v)
+				case dropOldest:
+					model = append(model[1:], v)
+				default:
+					if err != ErrFull {
+						t.Fatalf("op %d: Push on full stack = %v, want ErrFull", i, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("op %d: Push = %v", i, err)
+				}
+				highWater = max(highWater, len(model))
+			case 2:
+				got, ok := s.TryPop()
+				if ok != (len(model) > 0) {
+					t.Fatalf("op %d: TryPop ok = %t", i, ok)
+				}
+				if ok {
+					want := model[len(model)-1]
+					model = model[:len(model)-1]
+					if got != want {
+						t.Fatalf("op %d: TryPop = %d, want %d", i, got, want)
+					}
+				}
+			case 3:
+				if v%4 == 0 {
+					s.Clear()
+					model = nil
+				}
+			}
+
+			if err := s.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, append([]int{}, model...), s.ToSlice(), "after op %d (%d)", i, op)
+			assert.Equal(t, s.Cap()-len(model), s.Remaining())
+			assert.Equal(t, highWater, s.HighWaterMark())
+		}
+	})
+}
+
+func benchBoundedStackPushPop[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		s := NewBoundedStack[T](len(values), OverflowReject)
+		for _, v := range values {
+			_ = s.Push(v)
+		}
+		for !s.IsEmpty() {
+			s.Pop()
+		}
+	}
+}
+
+func BenchmarkBoundedStackPushPop(b *testing.B) {
+	runBench(b, benchBoundedStackPushPop[int], benchBoundedStackPushPop[string])
+}
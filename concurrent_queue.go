@@ -0,0 +1,132 @@
+package gocontainers
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// ConcurrentQueue is an unbounded FIFO queue that is safe for concurrent use
+// by multiple producers and consumers. It implements the Michael-Scott
+// lock-free queue: Enqueue and TryDequeue never block, they retry a
+// compare-and-swap until it succeeds.
+type ConcurrentQueue[T any] struct {
+	head atomic.Pointer[cqNode[T]] // dummy node; the front element is head.next
+	tail atomic.Pointer[cqNode[T]]
+	size atomic.Int64
+}
+
+type cqNode[T any] struct {
+	value T
+	next  atomic.Pointer[cqNode[T]]
+}
+
+func NewConcurrentQueue[T any]() *ConcurrentQueue[T] {
+	q := &ConcurrentQueue[T]{}
+	dummy := &cqNode[T]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// Enqueue adds element to the back of the queue.
+func (q *ConcurrentQueue[T]) Enqueue(element T) {
+	node := &cqNode[T]{value: element}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// tail is lagging behind, help the other enqueuer along
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, node) {
+			q.tail.CompareAndSwap(tail, node)
+			break
+		}
+	}
+	q.size.Add(1)
+}
+
+// TryDequeue removes and returns the element at the front of the queue.
+// It returns false if the queue is empty.
+func (q *ConcurrentQueue[T]) TryDequeue() (T, bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			var zero T
+			return zero, false
+		}
+		if head == tail {
+			// tail is lagging behind, help the other enqueuer along
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		// next becomes the new dummy. Its value is left in place because
+		// other dequeuers may still be reading it; it is released once the
+		// following element is dequeued.
+		value := next.value
+		if q.head.CompareAndSwap(head, next) {
+			q.size.Add(-1)
+			return value, true
+		}
+	}
+}
+
+// Drain dequeues every element currently in the queue and returns them in
+// FIFO order. Elements enqueued concurrently may or may not be included.
+func (q *ConcurrentQueue[T]) Drain() []T {
+	var result []T
+	for {
+		element, ok := q.TryDequeue()
+		if !ok {
+			return result
+		}
+		result = append(result, element)
+	}
+}
+
+// Len returns the number of elements in the queue. Under concurrent use the
+// result is approximate: it may lag behind operations that are in flight.
+func (q *ConcurrentQueue[T]) Len() int {
+	n := q.size.Load()
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+// IsEmpty reports whether the queue has no elements at the time of the call.
+func (q *ConcurrentQueue[T]) IsEmpty() bool {
+	return q.head.Load().next.Load() == nil
+}
+
+// CheckInvariants verifies that the tail is the last node and that the
+// size matches the number of linked elements. It is meant for tests and
+// must not run concurrently with other operations.
+func (q *ConcurrentQueue[T]) CheckInvariants() error {
+	head, tail := q.head.Load(), q.tail.Load()
+	if head == nil || tail == nil {
+		return fmt.Errorf("ConcurrentQueue: head or tail is nil")
+	}
+	count := int64(0)
+	last := head
+	for node := head.next.Load(); node != nil; node = node.next.Load() {
+		last = node
+		count++
+	}
+	if last != tail {
+		return fmt.Errorf("ConcurrentQueue: tail is not the last node")
+	}
+	if n := q.size.Load(); n != count {
+		return fmt.Errorf("ConcurrentQueue: size is %d but %d elements are linked", n, count)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package gocontainers
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentQueueFIFO(t *testing.T) {
+	q := NewConcurrentQueue[int]()
+	assert.True(t, q.IsEmpty())
+	assert.Equal(t, 0, q.Len())
+
+	for i := 1; i <= 3; i++ {
+		q.Enqueue(i)
+	}
+	assert.False(t, q.IsEmpty())
+	assert.Equal(t, 3, q.Len())
+
+	for i := 1; i <= 3; i++ {
+		v, ok := q.TryDequeue()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, q.IsEmpty())
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestConcurrentQueueEmptyDequeue(t *testing.T) {
+	q := NewConcurrentQueue[string]()
+	v, ok := q.TryDequeue()
+	assert.False(t, ok)
+	assert.Equal(t, "", v)
+
+	q.Enqueue("a")
+	q.TryDequeue()
+	_, ok = q.TryDequeue()
+	assert.False(t, ok)
+}
+
+func TestConcurrentQueueDrain(t *testing.T) {
+	q := NewConcurrentQueue[int]()
+	assert.Empty(t, q.Drain())
+
+	q.Enqueue(1)
+	q.Enqueue(2)
+	assert.Equal(t, []int{1, 2}, q.Drain())
+	assert.True(t, q.IsEmpty())
+}
+
+func TestConcurrentQueueStress(t *testing.T) {
+	const producers, consumers, perProducer = 8, 8, 5000
+	type msg struct{ producer, seq int }
+	q := NewConcurrentQueue[msg]()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Enqueue(msg{producer: p, seq: i})
+			}
+		}(p)
+	}
+
+	var mu sync.Mutex
+	received := make([][]int, producers)
+	var remaining atomic.Int64
+	remaining.Store(producers * perProducer)
+	var done sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			var local []msg
+			for remaining.Load() > 0 {
+				if m, ok := q.TryDequeue(); ok {
+					remaining.Add(-1)
+					local = append(local, m)
+				}
+			}
+			mu.Lock()
+			for _, m := range local {
+				received[m.producer] = append(received[m.producer], m.seq)
+			}
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	done.Wait()
+
+	total := 0
+	for p := 0; p < producers; p++ {
+		seen := make(map[int]bool, perProducer)
+		for _, seq := range received[p] {
+			assert.False(t, seen[seq], "duplicate message %d from producer %d", seq, p)
+			seen[seq] = true
+		}
+		total += len(received[p])
+	}
+	assert.Equal(t, producers*perProducer, total)
+	assert.True(t, q.IsEmpty())
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestConcurrentQueuePerProducerOrder(t *testing.T) {
+	const producers, perProducer = 4, 2000
+	q := NewConcurrentQueue[[2]int]()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Enqueue([2]int{p, i})
+			}
+		}(p)
+	}
+
+	// a single consumer must see each producer's messages in order
+	last := make([]int, producers)
+	for i := range last {
+		last[i] = -1
+	}
+	for got := 0; got < producers*perProducer; {
+		m, ok := q.TryDequeue()
+		if !ok {
+			continue
+		}
+		assert.Equal(t, last[m[0]]+1, m[1])
+		last[m[0]] = m[1]
+		got++
+	}
+	wg.Wait()
+}
+
+func BenchmarkConcurrentQueue(b *testing.B) {
+	q := NewConcurrentQueue[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Enqueue(1)
+			q.TryDequeue()
+		}
+	})
+}
+
+func BenchmarkMutexQueue(b *testing.B) {
+	q := NewQueue[int]()
+	var mu sync.Mutex
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mu.Lock()
+			q.Enqueue(1)
+			mu.Unlock()
+			mu.Lock()
+			if !q.IsEmpty() {
+				q.Dequeue()
+			}
+			mu.Unlock()
+		}
+	})
+}
+
+func FuzzConcurrentQueue(f *testing.F) {
+	f.Add([]byte{0, 8, 16, 2, 3, 10})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		q := NewConcurrentQueue[int]()
+		var model []int
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 4 {
+			case 0, 1:
+				q.Enqueue(v)
+				model = append(model, v)
+			case 2:
+				got, ok := q.TryDequeue()
+				if ok != (len(model) > 0) {
+					t.Fatalf("op %d: TryDequeue ok = %t", i, ok)
+				}
+				if ok {
+					if got != model[0] {
+						t.Fatalf("op %d: TryDequeue = %d, want %d", i, got, model[0])
+					}
+					model = model[1:]
+				}
+			case 3:
+				if v%4 == 0 {
+					if got := q.Drain(); !slices.Equal(got, model) {
+						t.Fatalf("op %d: Drain = %v, want %v", i, got, model)
+					}
+					model = nil
+				}
+			}
+
+			if err := q.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), q.Len())
+			assert.Equal(t, len(model) == 0, q.IsEmpty())
+		}
+	})
+}
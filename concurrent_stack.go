@@ -0,0 +1,98 @@
+package gocontainers
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// ConcurrentStack is an unbounded LIFO stack that is safe for concurrent use
+// by multiple goroutines. It is a Treiber stack: Push and TryPop swing the
+// top pointer with a compare-and-swap and retry on contention.
+//
+// The classic ABA problem (top is popped and a recycled node with the same
+// address pushed back between a load and its compare-and-swap) cannot occur
+// here: every Push allocates a fresh node and nodes are never reused, so the
+// garbage collector guarantees no node address is handed out again while any
+// goroutine still holds a reference to it.
+type ConcurrentStack[T any] struct {
+	top  atomic.Pointer[csNode[T]]
+	size atomic.Int64
+}
+
+type csNode[T any] struct {
+	value T
+	next  *csNode[T] // immutable once the node is published
+}
+
+func NewConcurrentStack[T any]() *ConcurrentStack[T] {
+	return &ConcurrentStack[T]{}
+}
+
+// Push adds element to the top of the stack.
+func (s *ConcurrentStack[T]) Push(element T) {
+	node := &csNode[T]{value: element}
+	for {
+		top := s.top.Load()
+		node.next = top
+		if s.top.CompareAndSwap(top, node) {
+			break
+		}
+	}
+	s.size.Add(1)
+}
+
+// TryPop removes and returns the element at the top of the stack.
+// It returns false if the stack is empty.
+func (s *ConcurrentStack[T]) TryPop() (T, bool) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			var zero T
+			return zero, false
+		}
+		if s.top.CompareAndSwap(top, top.next) {
+			s.size.Add(-1)
+			return top.value, true
+		}
+	}
+}
+
+// TryPeek returns the element at the top of the stack without removing it.
+// It returns false if the stack is empty.
+func (s *ConcurrentStack[T]) TryPeek() (T, bool) {
+	top := s.top.Load()
+	if top == nil {
+		var zero T
+		return zero, false
+	}
+	return top.value, true
+}
+
+// Size returns the number of elements in the stack. Under concurrent use the
+// result is approximate: it may lag behind operations that are in flight.
+func (s *ConcurrentStack[T]) Size() int {
+	n := s.size.Load()
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+// IsEmpty reports whether the stack has no elements at the time of the call.
+func (s *ConcurrentStack[T]) IsEmpty() bool {
+	return s.top.Load() == nil
+}
+
+// CheckInvariants verifies that the size matches the number of linked
+// elements. It is meant for tests and must not run concurrently with other
+// operations.
+func (s *ConcurrentStack[T]) CheckInvariants() error {
+	count := int64(0)
+	for node := s.top.Load(); node != nil; node = node.next {
+		count++
+	}
+	if n := s.size.Load(); n != count {
+		return fmt.Errorf("ConcurrentStack: size is %d but %d elements are linked", n, count)
+	}
+	return nil
+}
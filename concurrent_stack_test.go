@@ -0,0 +1,196 @@
+package gocontainers
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentStackLIFO(t *testing.T) {
+	s := NewConcurrentStack[int]()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	assert.Equal(t, 3, s.Size())
+
+	v, ok := s.TryPeek()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 3, s.Size())
+
+	for want := 3; want >= 1; want-- {
+		v, ok := s.TryPop()
+		assert.True(t, ok)
+		assert.Equal(t, want, v)
+	}
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+}
+
+func TestConcurrentStackEmpty(t *testing.T) {
+	s := NewConcurrentStack[string]()
+	v, ok := s.TryPop()
+	assert.False(t, ok)
+	assert.Equal(t, "", v)
+	v, ok = s.TryPeek()
+	assert.False(t, ok)
+	assert.Equal(t, "", v)
+}
+
+func TestConcurrentStackStress(t *testing.T) {
+	const workers, perWorker = 8, 5000
+	s := NewConcurrentStack[int]()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				s.Push(w*perWorker + i)
+			}
+		}(w)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool, workers*perWorker)
+	var remaining atomic.Int64
+	remaining.Store(workers * perWorker)
+	var done sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			var local []int
+			for remaining.Load() > 0 {
+				if v, ok := s.TryPop(); ok {
+					remaining.Add(-1)
+					local = append(local, v)
+				}
+			}
+			mu.Lock()
+			for _, v := range local {
+				assert.False(t, seen[v], "value %d popped twice", v)
+				seen[v] = true
+			}
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	done.Wait()
+	assert.Len(t, seen, workers*perWorker)
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+}
+
+// TestConcurrentStackFreeList hammers the push/pop/push pattern that
+// triggers ABA in stacks that recycle nodes: each worker takes a buffer
+// from the shared free-list and returns it, so the same values cycle
+// through the top of the stack many times.
+func TestConcurrentStackFreeList(t *testing.T) {
+	const buffers, workers, rounds = 4, 8, 5000
+	s := NewConcurrentStack[*[]byte]()
+	for i := 0; i < buffers; i++ {
+		buf := make([]byte, 8)
+		s.Push(&buf)
+	}
+
+	var inUse sync.Map
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				buf, ok := s.TryPop()
+				if !ok {
+					continue
+				}
+				if _, loaded := inUse.LoadOrStore(buf, true); loaded {
+					t.Error("buffer handed out twice")
+				}
+				inUse.Delete(buf)
+				s.Push(buf)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, buffers, s.Size())
+	count := 0
+	for !s.IsEmpty() {
+		s.TryPop()
+		count++
+	}
+	assert.Equal(t, buffers, count)
+}
+
+func BenchmarkConcurrentStack(b *testing.B) {
+	s := NewConcurrentStack[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Push(1)
+			s.TryPop()
+		}
+	})
+}
+
+func BenchmarkMutexStack(b *testing.B) {
+	s := NewStack[int]()
+	var mu sync.Mutex
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mu.Lock()
+			s.Push(1)
+			mu.Unlock()
+			mu.Lock()
+			if !s.IsEmpty() {
+				s.Pop()
+			}
+			mu.Unlock()
+		}
+	})
+}
+
+func FuzzConcurrentStack(f *testing.F) {
+	f.Add([]byte{0, 8, 16, 2, 3, 10})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := NewConcurrentStack[int]()
+		var model []int
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 3 {
+			case 0, 1:
+				s.Push(v)
+				model = append(model, v)
+			case 2:
+				got, ok := s.TryPop()
+				if ok != (len(model) > 0) {
+					t.Fatalf("op %d: TryPop ok = %t", i, ok)
+				}
+				if ok {
+					if want := model[len(model)-1]; got != want {
+						t.Fatalf("op %d: TryPop = %d, want %d", i, got, want)
+					}
+					model = model[:len(model)-1]
+				}
+			}
+
+			if err := s.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), s.Size())
+			top, ok := s.TryPeek()
+			assert.Equal(t, len(model) > 0, ok)
+			if ok {
+				assert.Equal(t, model[len(model)-1], top)
+			}
+		}
+	})
+}
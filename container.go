@@ -0,0 +1,57 @@
+package gocontainers
+
+// Container is the behaviour shared by every container in this package.
+type Container interface {
+	Size() int
+	IsEmpty() bool
+	Clear()
+}
+
+// Collection is a Container whose elements can be listed.
+type Collection[T any] interface {
+	Container
+	// ToSlice returns the elements in the container's natural order, or in
+	// unspecified order for unordered containers.
+	ToSlice() []T
+}
+
+// LIFO is a last-in, first-out Collection such as Stack.
+type LIFO[T any] interface {
+	Collection[T]
+	Push(element T)
+	Pop() T
+	Peek() T
+}
+
+// FIFO is a first-in, first-out Collection such as Queue.
+type FIFO[T any] interface {
+	Collection[T]
+	Enqueue(element T)
+	Dequeue() T
+	Peek() T
+}
+
+// PriorityQueue is a Collection that hands out items in priority order,
+// such as Heap.
+type PriorityQueue[T any] interface {
+	Collection[T]
+	PushItem(item *Item[T])
+	PopItem() *Item[T]
+	Peek() (*Item[T], bool)
+}
+
+var (
+	_ LIFO[int]          = (*Stack[int])(nil)
+	_ LIFO[int]          = (*AggregateStack[int])(nil)
+	_ FIFO[int]          = (*Queue[int])(nil)
+	_ FIFO[int]          = (*WindowQueue[int])(nil)
+	_ Collection[int]    = (*DLL[int])(nil)
+	_ Collection[int]    = (*Set[int])(nil)
+	_ Collection[int]    = (*MultiSet[int])(nil)
+	_ Collection[uint]   = (*BitSet)(nil)
+	_ Collection[uint32] = (*RoaringSet)(nil)
+	_ Collection[int]    = (*BoundedStack[int])(nil)
+	_ PriorityQueue[int] = (*Heap[int])(nil)
+	_ Container          = (*IntrusiveList[int])(nil)
+	_ Container          = (*History[int])(nil)
+)
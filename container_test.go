@@ -0,0 +1,245 @@
+package gocontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collectionCase describes how to build and fill one Collection
+// implementation for the shared conformance suites.
+type collectionCase struct {
+	name string
+	new  func() Collection[int]
+	add  func(c Collection[int], v int)
+}
+
+func collectionCases() []collectionCase {
+	return []collectionCase{
+		{
+			name: "Stack",
+			new:  func() Collection[int] { return NewStack[int]() },
+			add:  func(c Collection[int], v int) { c.(*Stack[int]).Push(v) },
+		},
+		{
+			name: "AggregateStack",
+			new:  func() Collection[int] { return NewAggregateStack(intLess) },
+			add:  func(c Collection[int], v int) { c.(*AggregateStack[int]).Push(v) },
+		},
+		{
+			name: "BoundedStack",
+			new:  func() Collection[int] { return NewBoundedStack[int](100, OverflowReject) },
+			add:  func(c Collection[int], v int) { _ = c.(*BoundedStack[int]).Push(v) },
+		},
+		{
+			name: "Queue",
+			new:  func() Collection[int] { return NewQueue[int]() },
+			add:  func(c Collection[int], v int) { c.(*Queue[int]).Enqueue(v) },
+		},
+		{
+			name: "WindowQueue",
+			new:  func() Collection[int] { return NewWindowQueue(intLess) },
+			add:  func(c Collection[int], v int) { c.(*WindowQueue[int]).Enqueue(v) },
+		},
+		{
+			name: "DLL",
+			new:  func() Collection[int] { return NewDLL[int]() },
+			add:  func(c Collection[int], v int) { c.(*DLL[int]).AddBack(NewNode(v)) },
+		},
+		{
+			name: "Set",
+			new:  func() Collection[int] { return NewSet[int]() },
+			add:  func(c Collection[int], v int) { c.(*Set[int]).Add(v) },
+		},
+		{
+			name: "MultiSet",
+			new:  func() Collection[int] { return NewMultiSet[int]() },
+			add:  func(c Collection[int], v int) { c.(*MultiSet[int]).Add(v, 1) },
+		},
+		{
+			name: "Heap",
+			new:  func() Collection[int] { return NewHeap(intLess) },
+			add:  func(c Collection[int], v int) { c.(*Heap[int]).PushItem(NewItem(v)) },
+		},
+	}
+}
+
+func TestCollectionConformance(t *testing.T) {
+	for _, tc := range collectionCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			testCollection(t, tc.new, tc.add, func(i int) int { return i })
+		})
+	}
+	t.Run("BitSet", func(t *testing.T) {
+		testCollection(t,
+			func() Collection[uint] { return NewBitSet(0) },
+			func(c Collection[uint], v uint) { c.(*BitSet).Add(v) },
+			func(i int) uint { return uint(i) })
+	})
+	t.Run("RoaringSet", func(t *testing.T) {
+		testCollection(t,
+			func() Collection[uint32] { return NewRoaringSet() },
+			func(c Collection[uint32], v uint32) { c.(*RoaringSet).Add(v) },
+			func(i int) uint32 { return uint32(i) << 12 })
+	})
+}
+
+// testCollection checks the Collection contract for one implementation.
+// value maps 1..100 to distinct elements.
+func testCollection[T comparable](t *testing.T, newC func() Collection[T], add func(Collection[T], T), value func(int) T) {
+	c := newC()
+	assert.True(t, c.IsEmpty())
+	assert.Equal(t, 0, c.Size())
+	assert.Empty(t, c.ToSlice())
+
+	var want []T
+	for i := 1; i <= 5; i++ {
+		add(c, value(i))
+		want = append(want, value(i))
+		assert.False(t, c.IsEmpty())
+		assert.Equal(t, i, c.Size())
+	}
+	assert.ElementsMatch(t, want, c.ToSlice())
+
+	// ToSlice returns a copy
+	s := c.ToSlice()
+	s[0] = value(100)
+	assert.NotContains(t, c.ToSlice(), value(100))
+
+	c.Clear()
+	assert.True(t, c.IsEmpty())
+	assert.Equal(t, 0, c.Size())
+	assert.Empty(t, c.ToSlice())
+
+	add(c, value(7))
+	assert.Equal(t, []T{value(7)}, c.ToSlice())
+}
+
+// TestContainerConformance covers the types that are only Containers:
+// Size and IsEmpty must agree however the container was filled.
+func TestContainerConformance(t *testing.T) {
+	type hooked struct {
+		hook ListHook[hooked]
+	}
+	impls := map[string]struct {
+		new func() Container
+		add func(c Container, i int)
+	}{
+		"IntrusiveList": {
+			new: func() Container {
+				return NewIntrusiveList(func(v *hooked) *ListHook[hooked] { return &v.hook })
+			},
+			add: func(c Container, i int) { c.(*IntrusiveList[hooked]).AddBack(&hooked{}) },
+		},
+		"History": {
+			new: func() Container { return NewHistory[int](0) },
+			add: func(c Container, i int) {
+				h := c.(*History[int])
+				h.Do(i)
+				if i%2 == 0 {
+					h.Undo()
+				}
+			},
+		},
+	}
+	for name, impl := range impls {
+		t.Run(name, func(t *testing.T) {
+			c := impl.new()
+			assert.True(t, c.IsEmpty())
+			assert.Equal(t, 0, c.Size())
+
+			for i := 1; i <= 5; i++ {
+				impl.add(c, i)
+				assert.False(t, c.IsEmpty())
+				assert.Equal(t, c.Size() == 0, c.IsEmpty())
+			}
+			if h, ok := c.(*History[int]); ok {
+				// only redo steps are left, which still count
+				for h.CanUndo() {
+					h.Undo()
+				}
+				assert.False(t, c.IsEmpty())
+				assert.NotZero(t, c.Size())
+			}
+
+			c.Clear()
+			assert.True(t, c.IsEmpty())
+			assert.Equal(t, 0, c.Size())
+		})
+	}
+}
+
+func TestLIFOConformance(t *testing.T) {
+	impls := map[string]func() LIFO[int]{
+		"Stack":          func() LIFO[int] { return NewStack[int]() },
+		"AggregateStack": func() LIFO[int] { return NewAggregateStack(intLess) },
+	}
+	for name, newLIFO := range impls {
+		t.Run(name, func(t *testing.T) {
+			s := newLIFO()
+			assert.Panics(t, func() { s.Pop() })
+			assert.Panics(t, func() { s.Peek() })
+
+			for i := 1; i <= 3; i++ {
+				s.Push(i)
+				assert.Equal(t, i, s.Peek())
+			}
+			assert.Equal(t, []int{1, 2, 3}, s.ToSlice())
+			for i := 3; i >= 1; i-- {
+				assert.Equal(t, i, s.Pop())
+			}
+			assert.True(t, s.IsEmpty())
+		})
+	}
+}
+
+func TestFIFOConformance(t *testing.T) {
+	impls := map[string]func() FIFO[int]{
+		"Queue":       func() FIFO[int] { return NewQueue[int]() },
+		"WindowQueue": func() FIFO[int] { return NewWindowQueue(intLess) },
+	}
+	for name, newFIFO := range impls {
+		t.Run(name, func(t *testing.T) {
+			q := newFIFO()
+			assert.Panics(t, func() { q.Dequeue() })
+			assert.Panics(t, func() { q.Peek() })
+
+			for i := 1; i <= 3; i++ {
+				q.Enqueue(i)
+				assert.Equal(t, 1, q.Peek())
+			}
+			assert.Equal(t, []int{1, 2, 3}, q.ToSlice())
+			assert.Equal(t, 1, q.Dequeue())
+			q.Enqueue(4)
+			assert.Equal(t, []int{2, 3, 4}, q.ToSlice())
+			for i := 2; i <= 4; i++ {
+				assert.Equal(t, i, q.Dequeue())
+			}
+			assert.True(t, q.IsEmpty())
+		})
+	}
+}
+
+func TestPriorityQueueConformance(t *testing.T) {
+	impls := map[string]func() PriorityQueue[int]{
+		"Heap": func() PriorityQueue[int] { return NewHeap(intLess) },
+	}
+	for name, newPQ := range impls {
+		t.Run(name, func(t *testing.T) {
+			pq := newPQ()
+			_, ok := pq.Peek()
+			assert.False(t, ok)
+
+			for _, v := range []int{5, 1, 4, 2, 3} {
+				pq.PushItem(NewItem(v))
+			}
+			top, ok := pq.Peek()
+			assert.True(t, ok)
+			assert.Equal(t, 1, top.Get())
+			for want := 1; want <= 5; want++ {
+				assert.Equal(t, want, pq.PopItem().Get())
+			}
+			assert.True(t, pq.IsEmpty())
+		})
+	}
+}
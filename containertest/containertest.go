@@ -0,0 +1,342 @@
+// Package containertest checks that container implementations behave like
+// the reference containers in gocontainers.
+//
+// Each TestXxx function runs three groups of subtests against fresh
+// instances built by the supplied constructor: table-driven behavioural
+// cases, a model-based run of random operations compared step by step with
+// the matching gocontainers reference type, and invariant checks after
+// every operation.
+//
+// Typical use, from a test in the implementing package:
+//
+//	func TestDiskQueue(t *testing.T) {
+//		containertest.TestFIFO(t, func() gocontainers.FIFO[int] {
+//			return newDiskQueue(t.TempDir())
+//		})
+//	}
+package containertest
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/puneetagr-dev/gocontainers"
+)
+
+// Set is the interface a set implementation must satisfy for TestSet.
+type Set[T comparable] interface {
+	gocontainers.Collection[T]
+	Add(element T)
+	Remove(element T)
+	Contains(element T) bool
+}
+
+// randomOps is the number of operations in each model-based run.
+const randomOps = 2000
+
+// CheckCollection fails t if c's Size, IsEmpty and ToSlice disagree.
+func CheckCollection[T any](t testing.TB, c gocontainers.Collection[T]) {
+	t.Helper()
+	size := c.Size()
+	if size < 0 {
+		t.Fatalf("Size() = %d, want >= 0", size)
+	}
+	if c.IsEmpty() != (size == 0) {
+		t.Fatalf("IsEmpty() = %v with Size() = %d", c.IsEmpty(), size)
+	}
+	if n := len(c.ToSlice()); n != size {
+		t.Fatalf("len(ToSlice()) = %d, Size() = %d", n, size)
+	}
+}
+
+func expectPanic(t *testing.T, op string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("%s on empty container did not panic", op)
+		}
+	}()
+	f()
+}
+
+func checkEqual[T comparable](t *testing.T, what string, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+}
+
+func checkSlice[T comparable](t *testing.T, what string, got, want []T) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+}
+
+// TestLIFO tests a LIFO implementation against gocontainers.Stack.
+func TestLIFO(t *testing.T, newLIFO func() gocontainers.LIFO[int]) {
+	t.Run("Behaviour", func(t *testing.T) {
+		cases := []struct {
+			name string
+			run  func(t *testing.T, s gocontainers.LIFO[int])
+		}{
+			{"Empty", func(t *testing.T, s gocontainers.LIFO[int]) {
+				CheckCollection[int](t, s)
+				checkEqual(t, "IsEmpty()", s.IsEmpty(), true)
+				expectPanic(t, "Pop", func() { s.Pop() })
+				expectPanic(t, "Peek", func() { s.Peek() })
+			}},
+			{"PushPopOrder", func(t *testing.T, s gocontainers.LIFO[int]) {
+				for i := 1; i <= 3; i++ {
+					s.Push(i)
+					checkEqual(t, "Peek()", s.Peek(), i)
+				}
+				checkSlice(t, "ToSlice()", s.ToSlice(), []int{1, 2, 3})
+				for i := 3; i >= 1; i-- {
+					checkEqual(t, "Pop()", s.Pop(), i)
+				}
+				checkEqual(t, "IsEmpty()", s.IsEmpty(), true)
+			}},
+			{"Clear", func(t *testing.T, s gocontainers.LIFO[int]) {
+				s.Push(1)
+				s.Push(2)
+				s.Clear()
+				CheckCollection[int](t, s)
+				checkEqual(t, "Size()", s.Size(), 0)
+				s.Push(3)
+				checkEqual(t, "Pop()", s.Pop(), 3)
+			}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) { tc.run(t, newLIFO()) })
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		s, model := newLIFO(), gocontainers.NewStack[int]()
+		rng := rand.New(rand.NewPCG(1, 1))
+		for i := 0; i < randomOps; i++ {
+			switch op := rng.IntN(10); {
+			case op < 5:
+				v := rng.Int()
+				s.Push(v)
+				model.Push(v)
+			case op < 8 && !model.IsEmpty():
+				checkEqual(t, "Pop()", s.Pop(), model.Pop())
+			case op < 9 && !model.IsEmpty():
+				checkEqual(t, "Peek()", s.Peek(), model.Peek())
+			case op == 9 && rng.IntN(20) == 0:
+				s.Clear()
+				model.Clear()
+			}
+			CheckCollection[int](t, s)
+			checkEqual(t, "Size()", s.Size(), model.Size())
+		}
+		checkSlice(t, "ToSlice()", s.ToSlice(), model.ToSlice())
+	})
+}
+
+// TestFIFO tests a FIFO implementation against gocontainers.Queue.
+func TestFIFO(t *testing.T, newFIFO func() gocontainers.FIFO[int]) {
+	t.Run("Behaviour", func(t *testing.T) {
+		cases := []struct {
+			name string
+			run  func(t *testing.T, q gocontainers.FIFO[int])
+		}{
+			{"Empty", func(t *testing.T, q gocontainers.FIFO[int]) {
+				CheckCollection[int](t, q)
+				checkEqual(t, "IsEmpty()", q.IsEmpty(), true)
+				expectPanic(t, "Dequeue", func() { q.Dequeue() })
+				expectPanic(t, "Peek", func() { q.Peek() })
+			}},
+			{"EnqueueDequeueOrder", func(t *testing.T, q gocontainers.FIFO[int]) {
+				for i := 1; i <= 3; i++ {
+					q.Enqueue(i)
+					checkEqual(t, "Peek()", q.Peek(), 1)
+				}
+				checkSlice(t, "ToSlice()", q.ToSlice(), []int{1, 2, 3})
+				for i := 1; i <= 3; i++ {
+					checkEqual(t, "Dequeue()", q.Dequeue(), i)
+				}
+				checkEqual(t, "IsEmpty()", q.IsEmpty(), true)
+			}},
+			{"Interleaved", func(t *testing.T, q gocontainers.FIFO[int]) {
+				q.Enqueue(1)
+				q.Enqueue(2)
+				checkEqual(t, "Dequeue()", q.Dequeue(), 1)
+				q.Enqueue(3)
+				checkSlice(t, "ToSlice()", q.ToSlice(), []int{2, 3})
+			}},
+			{"Clear", func(t *testing.T, q gocontainers.FIFO[int]) {
+				q.Enqueue(1)
+				q.Clear()
+				CheckCollection[int](t, q)
+				checkEqual(t, "Size()", q.Size(), 0)
+				q.Enqueue(2)
+				checkEqual(t, "Dequeue()", q.Dequeue(), 2)
+			}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) { tc.run(t, newFIFO()) })
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		q, model := newFIFO(), gocontainers.NewQueue[int]()
+		rng := rand.New(rand.NewPCG(2, 2))
+		for i := 0; i < randomOps; i++ {
+			switch op := rng.IntN(10); {
+			case op < 5:
+				v := rng.Int()
+				q.Enqueue(v)
+				model.Enqueue(v)
+			case op < 8 && !model.IsEmpty():
+				checkEqual(t, "Dequeue()", q.Dequeue(), model.Dequeue())
+			case op < 9 && !model.IsEmpty():
+				checkEqual(t, "Peek()", q.Peek(), model.Peek())
+			case op == 9 && rng.IntN(20) == 0:
+				q.Clear()
+				model.Clear()
+			}
+			CheckCollection[int](t, q)
+			checkEqual(t, "Size()", q.Size(), model.Size())
+		}
+		checkSlice(t, "ToSlice()", q.ToSlice(), model.ToSlice())
+	})
+}
+
+// TestSet tests a Set implementation against gocontainers.Set.
+func TestSet(t *testing.T, newSet func() Set[int]) {
+	t.Run("Behaviour", func(t *testing.T) {
+		cases := []struct {
+			name string
+			run  func(t *testing.T, s Set[int])
+		}{
+			{"Empty", func(t *testing.T, s Set[int]) {
+				CheckCollection[int](t, s)
+				checkEqual(t, "IsEmpty()", s.IsEmpty(), true)
+				checkEqual(t, "Contains(1)", s.Contains(1), false)
+				s.Remove(1)
+				checkEqual(t, "Size()", s.Size(), 0)
+			}},
+			{"AddIsIdempotent", func(t *testing.T, s Set[int]) {
+				s.Add(1)
+				s.Add(1)
+				checkEqual(t, "Size()", s.Size(), 1)
+				checkEqual(t, "Contains(1)", s.Contains(1), true)
+			}},
+			{"Remove", func(t *testing.T, s Set[int]) {
+				s.Add(1)
+				s.Add(2)
+				s.Remove(1)
+				s.Remove(3)
+				checkEqual(t, "Contains(1)", s.Contains(1), false)
+				checkSlice(t, "ToSlice()", s.ToSlice(), []int{2})
+			}},
+			{"Clear", func(t *testing.T, s Set[int]) {
+				s.Add(1)
+				s.Clear()
+				CheckCollection[int](t, s)
+				checkEqual(t, "Contains(1)", s.Contains(1), false)
+			}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) { tc.run(t, newSet()) })
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		s, model := newSet(), gocontainers.NewSet[int]()
+		rng := rand.New(rand.NewPCG(3, 3))
+		for i := 0; i < randomOps; i++ {
+			v := rng.IntN(64)
+			switch op := rng.IntN(10); {
+			case op < 5:
+				s.Add(v)
+				model.Add(v)
+			case op < 8:
+				s.Remove(v)
+				model.Remove(v)
+			case op < 9:
+				checkEqual(t, "Contains()", s.Contains(v), model.Contains(v))
+			case rng.IntN(20) == 0:
+				s.Clear()
+				model.Clear()
+			}
+			CheckCollection[int](t, s)
+			checkEqual(t, "Size()", s.Size(), model.Size())
+		}
+		got, want := s.ToSlice(), model.ToSlice()
+		slices.Sort(got)
+		slices.Sort(want)
+		checkSlice(t, "sorted ToSlice()", got, want)
+	})
+}
+
+// TestPriorityQueue tests a PriorityQueue implementation against
+// gocontainers.Heap. newPQ must return an empty queue ordered by less.
+func TestPriorityQueue(t *testing.T, newPQ func(less func(a, b int) bool) gocontainers.PriorityQueue[int]) {
+	less := func(a, b int) bool { return a < b }
+
+	t.Run("Behaviour", func(t *testing.T) {
+		cases := []struct {
+			name string
+			run  func(t *testing.T, pq gocontainers.PriorityQueue[int])
+		}{
+			{"Empty", func(t *testing.T, pq gocontainers.PriorityQueue[int]) {
+				CheckCollection[int](t, pq)
+				if _, ok := pq.Peek(); ok {
+					t.Fatal("Peek() on empty queue reported an item")
+				}
+			}},
+			{"PriorityOrder", func(t *testing.T, pq gocontainers.PriorityQueue[int]) {
+				for _, v := range []int{5, 1, 4, 2, 3} {
+					pq.PushItem(gocontainers.NewItem(v))
+				}
+				top, ok := pq.Peek()
+				if !ok {
+					t.Fatal("Peek() on non-empty queue reported no item")
+				}
+				checkEqual(t, "Peek()", top.Get(), 1)
+				for want := 1; want <= 5; want++ {
+					checkEqual(t, "PopItem()", pq.PopItem().Get(), want)
+				}
+			}},
+			{"Clear", func(t *testing.T, pq gocontainers.PriorityQueue[int]) {
+				pq.PushItem(gocontainers.NewItem(1))
+				pq.Clear()
+				CheckCollection[int](t, pq)
+				pq.PushItem(gocontainers.NewItem(2))
+				checkEqual(t, "PopItem()", pq.PopItem().Get(), 2)
+			}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) { tc.run(t, newPQ(less)) })
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		pq, model := newPQ(less), gocontainers.NewHeap(less)
+		rng := rand.New(rand.NewPCG(4, 4))
+		for i := 0; i < randomOps; i++ {
+			switch op := rng.IntN(10); {
+			case op < 5:
+				v := rng.IntN(1000)
+				pq.PushItem(gocontainers.NewItem(v))
+				model.PushItem(gocontainers.NewItem(v))
+			case op < 8 && !model.IsEmpty():
+				checkEqual(t, "PopItem()", pq.PopItem().Get(), model.PopItem().Get())
+			case op < 9 && !model.IsEmpty():
+				got, _ := pq.Peek()
+				want, _ := model.Peek()
+				checkEqual(t, "Peek()", got.Get(), want.Get())
+			case op == 9 && rng.IntN(20) == 0:
+				pq.Clear()
+				model.Clear()
+			}
+			CheckCollection[int](t, pq)
+			checkEqual(t, "Size()", pq.Size(), model.Size())
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package containertest
+
+import (
+	"testing"
+
+	"github.com/puneetagr-dev/gocontainers"
+)
+
+func TestReferenceStack(t *testing.T) {
+	TestLIFO(t, func() gocontainers.LIFO[int] { return gocontainers.NewStack[int]() })
+}
+
+func TestReferenceAggregateStack(t *testing.T) {
+	TestLIFO(t, func() gocontainers.LIFO[int] {
+		return gocontainers.NewAggregateStack(func(a, b int) bool { return a < b })
+	})
+}
+
+func TestReferenceQueue(t *testing.T) {
+	TestFIFO(t, func() gocontainers.FIFO[int] { return gocontainers.NewQueue[int]() })
+}
+
+func TestReferenceWindowQueue(t *testing.T) {
+	TestFIFO(t, func() gocontainers.FIFO[int] {
+		return gocontainers.NewWindowQueue(func(a, b int) bool { return a < b })
+	})
+}
+
+func TestReferenceSet(t *testing.T) {
+	TestSet(t, func() Set[int] { return gocontainers.NewSet[int]() })
+}
+
+func TestReferenceHeap(t *testing.T) {
+	TestPriorityQueue(t, func(less func(a, b int) bool) gocontainers.PriorityQueue[int] {
+		return gocontainers.NewHeap(less)
+	})
+}
+
+func TestReferenceBoundedStack(t *testing.T) {
+	TestLIFO(t, func() gocontainers.LIFO[int] {
+		return boundedLIFO{gocontainers.NewBoundedStack[int](1<<20, gocontainers.OverflowReject)}
+	})
+}
+
+func TestReferenceBitSet(t *testing.T) {
+	TestSet(t, func() Set[int] { return intSet[uint]{gocontainers.NewBitSet(0)} })
+}
+
+func TestReferenceRoaringSet(t *testing.T) {
+	TestSet(t, func() Set[int] { return intSet[uint32]{gocontainers.NewRoaringSet()} })
+}
+
+// boundedLIFO adapts BoundedStack, whose Push can fail, to LIFO. The stack
+// is made large enough that it never does.
+type boundedLIFO struct {
+	*gocontainers.BoundedStack[int]
+}
+
+func (b boundedLIFO) Push(element int) {
+	if err := b.BoundedStack.Push(element); err != nil {
+		panic(err)
+	}
+}
+
+// intSet adapts a set of unsigned integers to Set[int]. TestSet only uses
+// small non-negative values.
+type intSet[U uint | uint32] struct {
+	s Set[U]
+}
+
+func (s intSet[U]) Add(element int)           { s.s.Add(U(element)) }
+func (s intSet[U]) Remove(element int)        { s.s.Remove(U(element)) }
+func (s intSet[U]) Contains(element int) bool { return s.s.Contains(U(element)) }
+func (s intSet[U]) Size() int                 { return s.s.Size() }
+func (s intSet[U]) IsEmpty() bool             { return s.s.IsEmpty() }
+func (s intSet[U]) Clear()                    { s.s.Clear() }
+
+func (s intSet[U]) ToSlice() []int {
+	var result []int
+	for _, v := range s.s.ToSlice() {
+		result = append(result, int(v))
+	}
+	return result
+}
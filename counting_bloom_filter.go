@@ -0,0 +1,154 @@
+package gocontainers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// CountingBloomFilter is a BloomFilter that keeps a small counter instead
+// of a bit per slot, which makes Remove possible at the cost of eight times
+// the memory. Counters saturate at 255; a saturated counter is never
+// decremented, so heavy use can only raise the false-positive rate, never
+// produce a false negative.
+//
+// As with BloomFilter, filters that are combined or exchanged must use the
+// same Hasher; see Hasher.
+type CountingBloomFilter[T comparable] struct {
+	counters []uint8
+	k        int
+	hash     Hasher[T]
+	id       uint64 // hasherID of hash
+}
+
+// NewCountingBloomFilter returns a CountingBloomFilter sized to hold n
+// values with a false-positive rate of p, hashed with hash/maphash and the
+// process-wide default seed.
+func NewCountingBloomFilter[T comparable](n int, p float64) *CountingBloomFilter[T] {
+	return NewCountingBloomFilterWithHasher(n, p, defaultHasher[T]())
+}
+
+// NewCountingBloomFilterWithHasher is like NewCountingBloomFilter but uses
+// hash.
+func NewCountingBloomFilterWithHasher[T comparable](n int, p float64, hash Hasher[T]) *CountingBloomFilter[T] {
+	m, k := bloomParams(n, p, "CountingBloomFilter")
+	return &CountingBloomFilter[T]{counters: make([]uint8, m), k: k, hash: hash, id: hasherID(hash)}
+}
+
+// Add adds v to the filter.
+func (f *CountingBloomFilter[T]) Add(v T) {
+	h := f.hash(v)
+	m := uint64(len(f.counters))
+	for i := range f.k {
+		if j := bloomIndex(h, i, m); f.counters[j] < math.MaxUint8 {
+			f.counters[j]++
+		}
+	}
+}
+
+// Remove removes one occurrence of v and reports whether v may have been
+// present. Removing a value that was never added can cause false negatives
+// for other values, so only remove values known to have been added.
+func (f *CountingBloomFilter[T]) Remove(v T) bool {
+	if !f.MayContain(v) {
+		return false
+	}
+	h := f.hash(v)
+	m := uint64(len(f.counters))
+	for i := range f.k {
+		if j := bloomIndex(h, i, m); f.counters[j] < math.MaxUint8 {
+			f.counters[j]--
+		}
+	}
+	return true
+}
+
+// MayContain reports whether v may have been added. A false result means v
+// was definitely not added.
+func (f *CountingBloomFilter[T]) MayContain(v T) bool {
+	h := f.hash(v)
+	m := uint64(len(f.counters))
+	for i := range f.k {
+		if f.counters[bloomIndex(h, i, m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimatedSize estimates the number of distinct values in the filter from
+// the number of non-zero counters. It is +Inf once every counter is in use.
+func (f *CountingBloomFilter[T]) EstimatedSize() float64 {
+	var set uint64
+	for _, c := range f.counters {
+		if c != 0 {
+			set++
+		}
+	}
+	return bloomEstimate(set, uint64(len(f.counters)), f.k)
+}
+
+func (f *CountingBloomFilter[T]) IsEmpty() bool {
+	for _, c := range f.counters {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *CountingBloomFilter[T]) Clear() {
+	clear(f.counters)
+}
+
+// Union returns a filter holding the values of both filters, adding their
+// counters. It returns ErrIncompatible if the filters were sized or hashed
+// differently.
+func (f *CountingBloomFilter[T]) Union(other *CountingBloomFilter[T]) (*CountingBloomFilter[T], error) {
+	if len(f.counters) != len(other.counters) || f.k != other.k {
+		return nil, fmt.Errorf("CountingBloomFilter: %w: %d counters and %d hashes vs %d counters and %d hashes",
+			ErrIncompatible, len(f.counters), f.k, len(other.counters), other.k)
+	}
+	if f.id != other.id {
+		return nil, fmt.Errorf("CountingBloomFilter: %w: different hashers", ErrIncompatible)
+	}
+	counters := make([]uint8, len(f.counters))
+	for i, c := range f.counters {
+		counters[i] = uint8(min(int(c)+int(other.counters[i]), math.MaxUint8))
+	}
+	return &CountingBloomFilter[T]{counters: counters, k: f.k, hash: f.hash, id: f.id}, nil
+}
+
+// MarshalBinary encodes the filter's size, a fingerprint of its hasher and
+// its counters.
+func (f *CountingBloomFilter[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, bloomHeader+len(f.counters))
+	data = binary.LittleEndian.AppendUint32(data, uint32(f.k))
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(f.counters)))
+	data = binary.LittleEndian.AppendUint64(data, f.id)
+	return append(data, f.counters...), nil
+}
+
+// UnmarshalBinary replaces the size and contents of the filter with data
+// produced by MarshalBinary. The filter keeps its own hasher, and it
+// returns ErrIncompatible if the data was built with a different one.
+func (f *CountingBloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if f.hash == nil {
+		return fmt.Errorf("CountingBloomFilter: %w", ErrNoHasher)
+	}
+	if len(data) < bloomHeader {
+		return fmt.Errorf("CountingBloomFilter: %w: truncated header", ErrInvalidEncoding)
+	}
+	k := binary.LittleEndian.Uint32(data)
+	m := binary.LittleEndian.Uint64(data[4:])
+	if binary.LittleEndian.Uint64(data[12:]) != f.id {
+		return fmt.Errorf("CountingBloomFilter: %w: data was built with a different hasher", ErrIncompatible)
+	}
+	if k == 0 || m == 0 || k > 1<<16 || m != uint64(len(data)-bloomHeader) {
+		return fmt.Errorf("CountingBloomFilter: %w: %d counters and %d hashes in %d bytes",
+			ErrInvalidEncoding, m, k, len(data))
+	}
+	f.counters = append([]uint8(nil), data[bloomHeader:]...)
+	f.k = int(k)
+	return nil
+}
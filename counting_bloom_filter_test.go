@@ -0,0 +1,185 @@
+package gocontainers
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountingBloomFilterRemove(t *testing.T) {
+	f := NewCountingBloomFilter[string](100, 0.01)
+	assert.True(t, f.IsEmpty())
+
+	f.Add("a")
+	f.Add("b")
+	f.Add("a")
+	assert.True(t, f.MayContain("a"))
+
+	assert.True(t, f.Remove("a"))
+	assert.True(t, f.MayContain("a"), "a was added twice")
+	assert.True(t, f.Remove("a"))
+	assert.False(t, f.MayContain("a"))
+	assert.False(t, f.Remove("a"))
+	assert.True(t, f.MayContain("b"))
+
+	assert.True(t, f.Remove("b"))
+	assert.True(t, f.IsEmpty())
+}
+
+func TestCountingBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, p = 10000, 0.01
+	f := NewCountingBloomFilterWithHasher(n, p, mixHasher)
+	for i := range 2 * n {
+		f.Add(i)
+	}
+	// removing the upper half must leave the filter as if only the lower
+	// half had been added
+	for i := n; i < 2*n; i++ {
+		require.True(t, f.Remove(i))
+	}
+	for i := range n {
+		require.True(t, f.MayContain(i))
+	}
+
+	falsePositives := 0
+	for i := 2 * n; i < 12*n; i++ {
+		if f.MayContain(i) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/(10*n), 1.5*p)
+	assert.InDelta(t, n, f.EstimatedSize(), 0.05*n)
+}
+
+func TestCountingBloomFilterSaturation(t *testing.T) {
+	f := NewCountingBloomFilterWithHasher(10, 0.1, mixHasher)
+	for range 300 {
+		f.Add(1)
+	}
+	for range 300 {
+		f.Remove(1)
+	}
+	// saturated counters stay put, so 1 is still reported
+	assert.True(t, f.MayContain(1))
+	for _, c := range f.counters {
+		assert.True(t, c == 0 || c == math.MaxUint8)
+	}
+}
+
+func TestCountingBloomFilterUnion(t *testing.T) {
+	a := NewCountingBloomFilterWithHasher(100, 0.01, mixHasher)
+	b := NewCountingBloomFilterWithHasher(100, 0.01, mixHasher)
+	a.Add(1)
+	b.Add(1)
+	b.Add(2)
+
+	u, err := a.Union(b)
+	require.NoError(t, err)
+	assert.True(t, u.Remove(1))
+	assert.True(t, u.MayContain(1), "1 was in both filters")
+	assert.True(t, u.MayContain(2))
+
+	_, err = a.Union(NewCountingBloomFilterWithHasher(200, 0.01, mixHasher))
+	assert.True(t, errors.Is(err, ErrIncompatible))
+}
+
+func TestCountingBloomFilterBinary(t *testing.T) {
+	f := NewCountingBloomFilterWithHasher(100, 0.01, mixHasher)
+	f.Add(3)
+	f.Add(3)
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+
+	g := NewCountingBloomFilterWithHasher(1, 0.5, mixHasher)
+	require.NoError(t, g.UnmarshalBinary(data))
+	assert.Equal(t, f.counters, g.counters)
+	assert.True(t, g.Remove(3))
+	assert.True(t, g.MayContain(3))
+}
+
+func TestCountingBloomFilterHasherMismatch(t *testing.T) {
+	// filters built by the plain constructor share a hasher
+	a := NewCountingBloomFilter[string](100, 0.01)
+	b := NewCountingBloomFilter[string](100, 0.01)
+	a.Add("x")
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, b.UnmarshalBinary(data))
+	assert.True(t, b.MayContain("x"))
+	u, err := a.Union(b)
+	require.NoError(t, err)
+	assert.True(t, u.Remove("x"))
+	assert.True(t, u.MayContain("x"))
+
+	c := NewCountingBloomFilterWithHasher(100, 0.01, NewMaphashHasher[string]())
+	_, err = a.Union(c)
+	assert.True(t, errors.Is(err, ErrIncompatible))
+	assert.True(t, errors.Is(c.UnmarshalBinary(data), ErrIncompatible))
+}
+
+// FuzzCountingBloomFilterUnmarshal checks that UnmarshalBinary rejects
+// malformed input with ErrInvalidEncoding, or ErrIncompatible for another
+// hasher, and that accepted input is
+// exactly what MarshalBinary produces for the decoded filter.
+func FuzzCountingBloomFilterUnmarshal(f *testing.F) {
+	for _, n := range []int{1, 20} {
+		filter := NewCountingBloomFilterWithHasher(n, 0.01, mixHasher)
+		for i := range n / 2 {
+			filter.Add(i)
+		}
+		data, _ := filter.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := NewCountingBloomFilterWithHasher(1, 0.5, mixHasher)
+		if err := g.UnmarshalBinary(data); err != nil {
+			if !errors.Is(err, ErrInvalidEncoding) && !errors.Is(err, ErrIncompatible) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+		encoded, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded, data) {
+			t.Fatal("round trip changed the encoding")
+		}
+	})
+}
+
+func benchCountingBloomFilterAddRemove[T comparable](b *testing.B, values []T) {
+	f := NewCountingBloomFilter[T](len(values), 0.01)
+	for b.Loop() {
+		for _, v := range values {
+			f.Add(v)
+		}
+		for _, v := range values {
+			f.Remove(v)
+		}
+	}
+}
+
+func benchCountingBloomFilterContains[T comparable](b *testing.B, values []T) {
+	f := NewCountingBloomFilter[T](len(values), 0.01)
+	for _, v := range values {
+		f.Add(v)
+	}
+	for b.Loop() {
+		for _, v := range values {
+			f.MayContain(v)
+		}
+	}
+}
+
+func BenchmarkCountingBloomFilterAddRemove(b *testing.B) {
+	runBench(b, benchCountingBloomFilterAddRemove[int], benchCountingBloomFilterAddRemove[string])
+}
+
+func BenchmarkCountingBloomFilterContains(b *testing.B) {
+	runBench(b, benchCountingBloomFilterContains[int], benchCountingBloomFilterContains[string])
+}
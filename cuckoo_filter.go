@@ -0,0 +1,236 @@
+package gocontainers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// CuckooFilter is a probabilistic set that, unlike BloomFilter, supports
+// Delete. It stores a 16-bit fingerprint of each value in one of two
+// candidate buckets of four slots, moving existing fingerprints between
+// their buckets to make room. Lookup may report a value that was never
+// inserted with a probability of about 8/65536, and never misses one that
+// was.
+//
+// The same value may be inserted more than once and then needs as many
+// deletes. Up to eight copies fit, four in each candidate bucket, but when
+// both candidates are the same bucket only four do; that is always the case
+// in a filter with one bucket, and for some fingerprints at any size. As
+// with BloomFilter, filters that are exchanged with MarshalBinary must use
+// the same Hasher; see Hasher.
+type CuckooFilter[T comparable] struct {
+	buckets []cuckooBucket
+	mask    uint64 // len(buckets)-1; the bucket count is a power of two
+	count   int
+	hash    Hasher[T]
+	id      uint64 // hasherID of hash
+}
+
+// cuckooBucket holds up to cuckooSlots fingerprints; 0 marks an empty slot.
+type cuckooBucket [cuckooSlots]uint16
+
+const (
+	cuckooSlots = 4
+	// cuckooMaxKicks bounds the number of fingerprints moved by one Insert.
+	cuckooMaxKicks = 500
+	// cuckooTargetLoad is the load factor the filter is sized for; inserts
+	// usually start failing somewhat above it.
+	cuckooTargetLoad = 0.95
+)
+
+// NewCuckooFilter returns a CuckooFilter with room for about capacity
+// values, hashed with hash/maphash and the process-wide default seed.
+func NewCuckooFilter[T comparable](capacity int) *CuckooFilter[T] {
+	return NewCuckooFilterWithHasher(capacity, defaultHasher[T]())
+}
+
+// NewCuckooFilterWithHasher is like NewCuckooFilter but uses hash.
+func NewCuckooFilterWithHasher[T comparable](capacity int, hash Hasher[T]) *CuckooFilter[T] {
+	if capacity <= 0 {
+		panic("CuckooFilter capacity must be positive")
+	}
+	n := uint64(float64(capacity)/(cuckooSlots*cuckooTargetLoad)) + 1
+	n = 1 << bits.Len64(n-1)
+	return &CuckooFilter[T]{buckets: make([]cuckooBucket, n), mask: n - 1, hash: hash, id: hasherID(hash)}
+}
+
+// locate returns the fingerprint of v and its two candidate buckets.
+func (f *CuckooFilter[T]) locate(v T) (fp uint16, i1, i2 uint64) {
+	h := f.hash(v)
+	fp = uint16(h >> 48)
+	if fp == 0 {
+		fp = 1
+	}
+	i1 = h & f.mask
+	return fp, i1, f.altIndex(i1, fp)
+}
+
+// altIndex returns the other candidate bucket for fp given one of them.
+// Applying it twice gives back i, so a fingerprint can be moved without
+// knowing the value it came from.
+func (f *CuckooFilter[T]) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ uint64(fp)*0x5bd1e995) & f.mask
+}
+
+func (b *cuckooBucket) insert(fp uint16) bool {
+	for s, slot := range b {
+		if slot == 0 {
+			b[s] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (b *cuckooBucket) delete(fp uint16) bool {
+	for s, slot := range b {
+		if slot == fp {
+			b[s] = 0
+			return true
+		}
+	}
+	return false
+}
+
+func (b *cuckooBucket) contains(fp uint16) bool {
+	for _, slot := range b {
+		if slot == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Insert adds v to the filter. It returns ErrFull, leaving the filter
+// unchanged, if no room could be made for v.
+func (f *CuckooFilter[T]) Insert(v T) error {
+	fp, i1, i2 := f.locate(v)
+	if f.buckets[i1].insert(fp) || f.buckets[i2].insert(fp) {
+		f.count++
+		return nil
+	}
+
+	// evict fingerprints along a random path, remembering each swap so the
+	// path can be undone if it never reaches a free slot
+	type kick struct {
+		bucket uint64
+		slot   int
+		old    uint16
+	}
+	path := make([]kick, 0, cuckooMaxKicks)
+	i := i1
+	if rand.IntN(2) == 0 {
+		i = i2
+	}
+	for range cuckooMaxKicks {
+		s := rand.IntN(cuckooSlots)
+		path = append(path, kick{bucket: i, slot: s, old: f.buckets[i][s]})
+		fp, f.buckets[i][s] = f.buckets[i][s], fp
+		i = f.altIndex(i, fp)
+		if f.buckets[i].insert(fp) {
+			f.count++
+			return nil
+		}
+	}
+	for k := len(path) - 1; k >= 0; k-- {
+		f.buckets[path[k].bucket][path[k].slot] = path[k].old
+	}
+	return fmt.Errorf("CuckooFilter: %w: load factor %.3f", ErrFull, f.LoadFactor())
+}
+
+// Lookup reports whether v may have been inserted. A false result means v
+// is definitely not in the filter.
+func (f *CuckooFilter[T]) Lookup(v T) bool {
+	fp, i1, i2 := f.locate(v)
+	return f.buckets[i1].contains(fp) || f.buckets[i2].contains(fp)
+}
+
+// Delete removes one copy of v and reports whether one was found. Deleting
+// a value that was never inserted may remove another value that shares its
+// fingerprint, so only delete values known to have been inserted.
+func (f *CuckooFilter[T]) Delete(v T) bool {
+	fp, i1, i2 := f.locate(v)
+	if f.buckets[i1].delete(fp) || f.buckets[i2].delete(fp) {
+		f.count--
+		return true
+	}
+	return false
+}
+
+// Count returns the number of fingerprints stored, which is the number of
+// inserts minus the number of successful deletes.
+func (f *CuckooFilter[T]) Count() int {
+	return f.count
+}
+
+// Capacity returns the number of fingerprint slots.
+func (f *CuckooFilter[T]) Capacity() int {
+	return len(f.buckets) * cuckooSlots
+}
+
+// LoadFactor returns the fraction of slots in use.
+func (f *CuckooFilter[T]) LoadFactor() float64 {
+	return float64(f.count) / float64(f.Capacity())
+}
+
+func (f *CuckooFilter[T]) IsEmpty() bool {
+	return f.count == 0
+}
+
+func (f *CuckooFilter[T]) Clear() {
+	clear(f.buckets)
+	f.count = 0
+}
+
+// cuckooHeader is the encoded size of the bucket count and the hasher
+// fingerprint.
+const cuckooHeader = 16
+
+// MarshalBinary encodes a fingerprint of the filter's hasher and its
+// buckets.
+func (f *CuckooFilter[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, cuckooHeader+len(f.buckets)*cuckooSlots*2)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(f.buckets)))
+	data = binary.LittleEndian.AppendUint64(data, f.id)
+	for _, b := range f.buckets {
+		for _, fp := range b {
+			data = binary.LittleEndian.AppendUint16(data, fp)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the size and contents of the filter with data
+// produced by MarshalBinary. The filter keeps its own hasher, and it
+// returns ErrIncompatible if the data was built with a different one.
+func (f *CuckooFilter[T]) UnmarshalBinary(data []byte) error {
+	if f.hash == nil {
+		return fmt.Errorf("CuckooFilter: %w", ErrNoHasher)
+	}
+	if len(data) < cuckooHeader {
+		return fmt.Errorf("CuckooFilter: %w: truncated header", ErrInvalidEncoding)
+	}
+	n := binary.LittleEndian.Uint64(data)
+	if binary.LittleEndian.Uint64(data[8:]) != f.id {
+		return fmt.Errorf("CuckooFilter: %w: data was built with a different hasher", ErrIncompatible)
+	}
+	if n == 0 || n&(n-1) != 0 || n > uint64(len(data)) || uint64(len(data)-cuckooHeader) != n*cuckooSlots*2 {
+		return fmt.Errorf("CuckooFilter: %w: %d buckets in %d bytes", ErrInvalidEncoding, n, len(data))
+	}
+	buckets := make([]cuckooBucket, n)
+	count := 0
+	pos := cuckooHeader
+	for i := range buckets {
+		for s := range buckets[i] {
+			buckets[i][s] = binary.LittleEndian.Uint16(data[pos:])
+			pos += 2
+			if buckets[i][s] != 0 {
+				count++
+			}
+		}
+	}
+	f.buckets, f.mask, f.count = buckets, n-1, count
+	return nil
+}
@@ -0,0 +1,223 @@
+package gocontainers
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCuckooFilterInsertDelete(t *testing.T) {
+	f := NewCuckooFilter[string](100)
+	assert.True(t, f.IsEmpty())
+	assert.False(t, f.Lookup("a"))
+
+	require.NoError(t, f.Insert("a"))
+	require.NoError(t, f.Insert("b"))
+	require.NoError(t, f.Insert("a"))
+	assert.True(t, f.Lookup("a"))
+	assert.True(t, f.Lookup("b"))
+	assert.Equal(t, 3, f.Count())
+
+	assert.True(t, f.Delete("a"))
+	assert.True(t, f.Lookup("a"), "a was inserted twice")
+	assert.True(t, f.Delete("a"))
+	assert.False(t, f.Lookup("a"))
+	assert.False(t, f.Delete("a"))
+	assert.Equal(t, 1, f.Count())
+
+	f.Clear()
+	assert.True(t, f.IsEmpty())
+	assert.False(t, f.Lookup("b"))
+}
+
+func TestCuckooFilterSizing(t *testing.T) {
+	f := NewCuckooFilter[int](1000)
+	assert.Equal(t, 2048, f.Capacity())
+	assert.Equal(t, 4, NewCuckooFilter[int](1).Capacity())
+	assert.Panics(t, func() { NewCuckooFilter[int](0) })
+}
+
+func TestCuckooFilterFull(t *testing.T) {
+	f := NewCuckooFilterWithHasher(1000, mixHasher)
+	var inserted []int
+	failures := 0
+	for i := 0; failures < 10; i++ {
+		snapshot := append([]cuckooBucket(nil), f.buckets...)
+		err := f.Insert(i)
+		if err == nil {
+			inserted = append(inserted, i)
+			continue
+		}
+		// a failed insert must not drop anything along its kick path
+		require.True(t, errors.Is(err, ErrFull))
+		require.Equal(t, snapshot, f.buckets)
+		failures++
+	}
+	assert.Greater(t, f.LoadFactor(), 0.9)
+	assert.Equal(t, len(inserted), f.Count())
+	for _, v := range inserted {
+		require.True(t, f.Lookup(v), v)
+	}
+
+	for _, v := range inserted {
+		require.True(t, f.Delete(v), v)
+	}
+	assert.True(t, f.IsEmpty())
+	assert.Equal(t, make([]cuckooBucket, len(f.buckets)), f.buckets)
+}
+
+func TestCuckooFilterFalsePositiveRate(t *testing.T) {
+	const n = 10000
+	f := NewCuckooFilterWithHasher(n, mixHasher)
+	for i := range n {
+		require.NoError(t, f.Insert(i))
+	}
+
+	falsePositives := 0
+	for i := n; i < 101*n; i++ {
+		if f.Lookup(i) {
+			falsePositives++
+		}
+	}
+	// 2 buckets of 4 slots against 16-bit fingerprints gives at most 8/65536
+	assert.Less(t, float64(falsePositives)/(100*n), 8.0/65536)
+}
+
+func TestCuckooFilterBinary(t *testing.T) {
+	f := NewCuckooFilterWithHasher(100, mixHasher)
+	for i := range 50 {
+		require.NoError(t, f.Insert(i))
+	}
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+
+	g := NewCuckooFilterWithHasher(1, mixHasher)
+	require.NoError(t, g.UnmarshalBinary(data))
+	assert.Equal(t, f.Count(), g.Count())
+	assert.Equal(t, f.Capacity(), g.Capacity())
+	for i := range 50 {
+		assert.True(t, g.Lookup(i))
+	}
+	assert.True(t, g.Delete(0))
+	assert.False(t, g.Lookup(0))
+
+	// a bucket count that is not a power of two
+	bad := append([]byte(nil), data[:cuckooHeader+3*cuckooSlots*2]...)
+	bad[0], bad[1] = 3, 0
+	clear(bad[2:8])
+	assert.True(t, errors.Is(g.UnmarshalBinary(bad), ErrInvalidEncoding))
+}
+
+func TestCuckooFilterHasherMismatch(t *testing.T) {
+	// filters built by the plain constructor share a hasher
+	f := NewCuckooFilter[int](100)
+	for i := range 50 {
+		require.NoError(t, f.Insert(i))
+	}
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	g := NewCuckooFilter[int](1)
+	require.NoError(t, g.UnmarshalBinary(data))
+	for i := range 50 {
+		require.True(t, g.Lookup(i))
+	}
+
+	h := NewCuckooFilterWithHasher(1, NewMaphashHasher[int]())
+	assert.True(t, errors.Is(h.UnmarshalBinary(data), ErrIncompatible))
+	assert.Equal(t, 4, h.Capacity(), "a rejected encoding leaves the filter unchanged")
+}
+
+func TestCuckooFilterDuplicates(t *testing.T) {
+	// with a single bucket both candidates coincide, so only four copies fit
+	f := NewCuckooFilterWithHasher(1, mixHasher)
+	for range cuckooSlots {
+		require.NoError(t, f.Insert(7))
+	}
+	assert.True(t, errors.Is(f.Insert(7), ErrFull))
+
+	// otherwise a value whose candidates differ fits eight times
+	f = NewCuckooFilterWithHasher(1000, mixHasher)
+	v := 0
+	for {
+		if _, i1, i2 := f.locate(v); i1 != i2 {
+			break
+		}
+		v++
+	}
+	for range 2 * cuckooSlots {
+		require.NoError(t, f.Insert(v))
+	}
+	assert.True(t, errors.Is(f.Insert(v), ErrFull))
+	assert.Equal(t, 2*cuckooSlots, f.Count())
+}
+
+// FuzzCuckooFilterUnmarshal checks that UnmarshalBinary rejects malformed
+// input with ErrInvalidEncoding, or ErrIncompatible for another hasher, and
+// that accepted input is exactly what MarshalBinary produces for the decoded
+// filter.
+func FuzzCuckooFilterUnmarshal(f *testing.F) {
+	for _, n := range []int{1, 50} {
+		filter := NewCuckooFilterWithHasher(n, mixHasher)
+		for i := range n / 2 {
+			_ = filter.Insert(i)
+		}
+		data, _ := filter.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := NewCuckooFilterWithHasher(1, mixHasher)
+		if err := g.UnmarshalBinary(data); err != nil {
+			if !errors.Is(err, ErrInvalidEncoding) && !errors.Is(err, ErrIncompatible) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+		encoded, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded, data) {
+			t.Fatal("round trip changed the encoding")
+		}
+		if g.Count() > g.Capacity() {
+			t.Fatalf("Count %d exceeds Capacity %d", g.Count(), g.Capacity())
+		}
+	})
+}
+
+// The cuckoo benchmarks size the filter at twice the number of values so
+// inserts never fail.
+func benchCuckooFilterInsertDelete[T comparable](b *testing.B, values []T) {
+	f := NewCuckooFilter[T](2 * len(values))
+	for b.Loop() {
+		for _, v := range values {
+			_ = f.Insert(v)
+		}
+		for _, v := range values {
+			f.Delete(v)
+		}
+	}
+}
+
+func benchCuckooFilterContains[T comparable](b *testing.B, values []T) {
+	f := NewCuckooFilter[T](2 * len(values))
+	for _, v := range values {
+		_ = f.Insert(v)
+	}
+	for b.Loop() {
+		for _, v := range values {
+			f.Lookup(v)
+		}
+	}
+}
+
+func BenchmarkCuckooFilterInsertDelete(b *testing.B) {
+	runBench(b, benchCuckooFilterInsertDelete[int], benchCuckooFilterInsertDelete[string])
+}
+
+func BenchmarkCuckooFilterContains(b *testing.B) {
+	runBench(b, benchCuckooFilterContains[int], benchCuckooFilterContains[string])
+}
@@ -0,0 +1,7 @@
+//go:build !debug
+
+package gocontainers
+
+// debug enables extra runtime checks, such as detecting concurrent
+// modification of a DLL under a Cursor. Build with -tags debug to turn it on.
+const debug = false
@@ -1,10 +1,12 @@
 package gocontainers
 
-type Queue[T comparable] struct {
+import "slices"
+
+type Queue[T any] struct {
 	elements []T
 }
 
-func NewQueue[T comparable]() *Queue[T] {
+func NewQueue[T any]() *Queue[T] {
 	return &Queue[T]{elements: make([]T, 0)}
 }
 
@@ -33,3 +35,22 @@ func (q *Queue[T]) IsEmpty() bool {
 func (q *Queue[T]) Clear() {
 	q.elements = nil
 }
+
+func (q *Queue[T]) Peek() T {
+	if len(q.elements) == 0 {
+		panic("Peek from empty queue")
+	}
+	return q.elements[0]
+}
+
+// ContainsFunc reports whether at least one element e satisfies f(e).
+func (q *Queue[T]) ContainsFunc(f func(T) bool) bool {
+	return slices.ContainsFunc(q.elements, f)
+}
+
+// ToSlice returns the elements from front to back.
+func (q *Queue[T]) ToSlice() []T {
+	result := make([]T, len(q.elements))
+	copy(result, q.elements)
+	return result
+}
@@ -143,3 +143,136 @@ func TestQueue_Clear(t *testing.T) {
 		})
 	}
 }
+
+func TestQueue_NonComparable(t *testing.T) {
+	q := NewQueue[[]byte]()
+	q.Enqueue([]byte("first"))
+	q.Enqueue([]byte("second"))
+
+	if got := string(q.Dequeue()); got != "first" {
+		t.Errorf("Dequeue should return first, got %s", got)
+	}
+	if got := string(q.Dequeue()); got != "second" {
+		t.Errorf("Dequeue should return second, got %s", got)
+	}
+}
+
+func TestQueue_ContainsFunc(t *testing.T) {
+	q := NewQueue[[]byte]()
+	if q.ContainsFunc(func([]byte) bool { return true }) {
+		t.Error("Empty queue should contain nothing")
+	}
+	q.Enqueue([]byte("first"))
+	q.Enqueue([]byte("second"))
+	if !q.ContainsFunc(func(b []byte) bool { return string(b) == "second" }) {
+		t.Error("Queue should contain second")
+	}
+	q.Dequeue()
+	if q.ContainsFunc(func(b []byte) bool { return string(b) == "first" }) {
+		t.Error("Queue should not contain first after it was dequeued")
+	}
+}
+
+// FuzzQueue applies a random sequence of operations, one per input byte, to
+// a Queue and a slice model and checks that they agree after every step.
+func FuzzQueue(f *testing.F) {
+	f.Add([]byte{0, 8, 1, 2, 1})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		q := NewQueue[int]()
+		var model []int
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 3 {
+			case 0:
+				q.Enqueue(v)
+				model = append(model, v)
+			case 1:
+				if len(model) > 0 {
+					want := model[0]
+					model = model[1:]
+					if got := q.Dequeue(); got != want {
+						t.Fatalf("op %d: Dequeue = %d, want %d", i, got, want)
+					}
+				}
+			case 2:
+				if v%4 == 0 {
+					q.Clear()
+					model = nil
+				}
+			}
+
+			if q.Size() != len(model) || q.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Size = %d, want %d", i, q.Size(), len(model))
+			}
+			if len(model) > 0 && q.Peek() != model[0] {
+				t.Fatalf("op %d: Peek = %d, want %d", i, q.Peek(), model[0])
+			}
+		}
+	})
+}
+
+func benchQueueEnqueueDequeue[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		q := NewQueue[T]()
+		for _, v := range values {
+			q.Enqueue(v)
+		}
+		for !q.IsEmpty() {
+			q.Dequeue()
+		}
+	}
+}
+
+// benchQueueSteadyState keeps the queue at len(values) elements while
+// cycling through it, which is where reslicing in Dequeue shows up.
+func benchQueueSteadyState[T any](b *testing.B, values []T) {
+	q := NewQueue[T]()
+	for _, v := range values {
+		q.Enqueue(v)
+	}
+	for b.Loop() {
+		for range values {
+			q.Enqueue(q.Dequeue())
+		}
+	}
+}
+
+func benchQueueIterate[T any](b *testing.B, values []T) {
+	q := NewQueue[T]()
+	for _, v := range values {
+		q.Enqueue(v)
+	}
+	for b.Loop() {
+		for range q.elements {
+		}
+	}
+}
+
+// benchQueueContains looks up the back element, the worst case for a
+// front-to-back scan.
+func benchQueueContains[T comparable](b *testing.B, values []T) {
+	q := NewQueue[T]()
+	for _, v := range values {
+		q.Enqueue(v)
+	}
+	target := values[len(values)-1]
+	for b.Loop() {
+		q.ContainsFunc(func(v T) bool { return v == target })
+	}
+}
+
+func BenchmarkQueueEnqueueDequeue(b *testing.B) {
+	runBench(b, benchQueueEnqueueDequeue[int], benchQueueEnqueueDequeue[string])
+}
+
+func BenchmarkQueueSteadyState(b *testing.B) {
+	runBench(b, benchQueueSteadyState[int], benchQueueSteadyState[string])
+}
+
+func BenchmarkQueueIterate(b *testing.B) {
+	runBench(b, benchQueueIterate[int], benchQueueIterate[string])
+}
+
+func BenchmarkQueueContains(b *testing.B) {
+	runBench(b, benchQueueContains[int], benchQueueContains[string])
+}
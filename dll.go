@@ -1,18 +1,35 @@
 package gocontainers
 
-type DLL[T comparable] struct {
-	head *Node[T]
-	tail *Node[T]
-	size int
+import "fmt"
+
+type DLL[T any] struct {
+	head  *Node[T]
+	tail  *Node[T]
+	size  int
+	mods  int        // bumped on every structural change, see Cursor
+	token *listToken // carried by the nodes in the list, nil until one is added
 }
 
-type Node[T comparable] struct {
+type Node[T any] struct {
 	element T
 	prev    *Node[T]
 	next    *Node[T]
+	list    *listToken // token of the list or ring the node is in, nil if none
+}
+
+// listToken identifies the nodes of one list or ring. Clear retires the
+// token rather than visiting every node, so a node is linked only while
+// its token is live.
+type listToken struct {
+	retired bool
+}
+
+// linked reports whether the node is in a list or ring.
+func (n *Node[T]) linked() bool {
+	return n.list != nil && !n.list.retired
 }
 
-func NewNode[T comparable](element T) *Node[T] {
+func NewNode[T any](element T) *Node[T] {
 	return &Node[T]{element: element}
 }
 
@@ -24,20 +41,30 @@ func (n *Node[T]) Update(val T) {
 	n.element = val
 }
 
+// Next returns the node after n. Once n has been deleted from its list it
+// still returns the node that followed it, so a list can be walked with
+// Next while deleting the nodes visited.
 func (n *Node[T]) Next() *Node[T] {
 	return n.next
 }
 
+// Prev returns the node before n, and like Next keeps returning it once n
+// has been deleted.
 func (n *Node[T]) Prev() *Node[T] {
 	return n.prev
 }
 
-func NewDLL[T comparable]() *DLL[T] {
+func NewDLL[T any]() *DLL[T] {
 	return &DLL[T]{head: nil, tail: nil, size: 0}
 }
 
+// AddFront links node in at the front of the list. It panics if node is
+// already in a list or ring.
 func (dll *DLL[T]) AddFront(node *Node[T]) {
+	dll.link(node)
+	node.prev = nil
 	if dll.head == nil {
+		node.next = nil
 		dll.head = node
 		dll.tail = node
 	} else {
@@ -46,10 +73,16 @@ func (dll *DLL[T]) AddFront(node *Node[T]) {
 		dll.head = node
 	}
 	dll.size++
+	dll.mods++
 }
 
+// AddBack links node in at the back of the list. It panics if node is
+// already in a list or ring.
 func (dll *DLL[T]) AddBack(node *Node[T]) {
+	dll.link(node)
+	node.next = nil
 	if dll.tail == nil {
+		node.prev = nil
 		dll.head = node
 		dll.tail = node
 	} else {
@@ -58,36 +91,19 @@ func (dll *DLL[T]) AddBack(node *Node[T]) {
 		dll.tail = node
 	}
 	dll.size++
+	dll.mods++
 }
 
 func (dll *DLL[T]) RemoveFront() {
-	if dll.head == nil {
-		return
-	}
-	if dll.head == dll.tail {
-		dll.head = nil
-		dll.tail = nil
-		dll.size = 0
-		return
+	if dll.head != nil {
+		dll.unlink(dll.head)
 	}
-	dll.head = dll.head.next
-	dll.head.prev = nil
-	dll.size--
 }
 
 func (dll *DLL[T]) RemoveBack() {
-	if dll.tail == nil {
-		return
-	}
-	if dll.head == dll.tail {
-		dll.head = nil
-		dll.tail = nil
-		dll.size = 0
-		return
+	if dll.tail != nil {
+		dll.unlink(dll.tail)
 	}
-	dll.tail = dll.tail.prev
-	dll.tail.next = nil
-	dll.size--
 }
 
 func (dll *DLL[T]) Size() int {
@@ -106,71 +122,192 @@ func (dll *DLL[T]) GetBack() *Node[T] {
 	return dll.tail
 }
 
-func (dll *DLL[T]) DeleteMatch(element T) {
+// DeleteMatchFunc removes every node whose element satisfies match.
+func (dll *DLL[T]) DeleteMatchFunc(match func(T) bool) {
 	if dll.head == nil {
 		return
 	}
 
 	current := dll.head
 	for current != nil {
-		if current.element == element {
-			next := current.next
-			if current.prev != nil {
-				current.prev.next = current.next
-			} else {
-				dll.head = current.next
-			}
-			if current.next != nil {
-				current.next.prev = current.prev
-			} else {
-				dll.tail = current.prev
-			}
-			dll.size--
-			current = next
-		} else {
-			current = current.next
+		next := current.next
+		if match(current.element) {
+			dll.unlink(current)
+		}
+		current = next
+	}
+}
+
+// DeleteMatch removes every node of dll whose element equals element.
+func DeleteMatch[T comparable](dll *DLL[T], element T) {
+	dll.DeleteMatchFunc(func(e T) bool { return e == element })
+}
+
+// FindFunc returns the first node from the front whose element satisfies
+// match, or nil if there is none.
+func (dll *DLL[T]) FindFunc(match func(T) bool) *Node[T] {
+	for node := dll.head; node != nil; node = node.next {
+		if match(node.element) {
+			return node
 		}
 	}
+	return nil
 }
 
+// Find returns the first node from the front of dll whose element equals
+// element, or nil if there is none.
+func Find[T comparable](dll *DLL[T], element T) *Node[T] {
+	return dll.FindFunc(func(e T) bool { return e == element })
+}
+
+// DeleteNode removes node from the list. It does nothing if node is not on
+// this list, for example because it was already deleted. node.Next still
+// returns the node that followed it, see Node.Next.
 func (dll *DLL[T]) DeleteNode(node *Node[T]) {
-	if dll.Size() == 0 {
+	if !dll.owns(node) {
 		return
 	}
+	dll.unlink(node)
+}
 
-	if dll.size == 1 && dll.head == node {
-		dll.head = nil
-		dll.tail = nil
-		dll.size = 0
-		return
+// Clear removes all elements from the DLL in constant time. The nodes keep
+// their links, but no longer belong to the list, so deleting one of them
+// afterwards does nothing and they may be added to a list again.
+func (dll *DLL[T]) Clear() {
+	if dll.token != nil {
+		dll.token.retired = true
+		dll.token = nil
 	}
+	dll.head = nil
+	dll.tail = nil
+	dll.size = 0
+	dll.mods++
+}
 
-	if dll.head == node {
-		dll.head = dll.head.next
-		dll.tail.prev = nil
-		dll.size--
+// ToSlice returns the elements from front to back.
+func (dll *DLL[T]) ToSlice() []T {
+	result := make([]T, 0, dll.size)
+	for node := dll.head; node != nil; node = node.next {
+		result = append(result, node.element)
+	}
+	return result
+}
+
+// insertBefore links node into the list immediately before mark.
+// A nil mark appends node at the back.
+func (dll *DLL[T]) insertBefore(mark, node *Node[T]) {
+	if mark == nil {
+		dll.AddBack(node)
 		return
 	}
+	dll.link(node)
+	node.prev = mark.prev
+	node.next = mark
+	if mark.prev != nil {
+		mark.prev.next = node
+	} else {
+		dll.head = node
+	}
+	mark.prev = node
+	dll.size++
+	dll.mods++
+}
 
-	if dll.tail == node {
-		dll.tail = dll.tail.prev
-		dll.head.next = nil
-		dll.size--
+// insertAfter links node into the list immediately after mark.
+// A nil mark prepends node at the front.
+func (dll *DLL[T]) insertAfter(mark, node *Node[T]) {
+	if mark == nil {
+		dll.AddFront(node)
 		return
 	}
+	dll.link(node)
+	node.prev = mark
+	node.next = mark.next
+	if mark.next != nil {
+		mark.next.prev = node
+	} else {
+		dll.tail = node
+	}
+	mark.next = node
+	dll.size++
+	dll.mods++
+}
 
-	node.prev.next, node.next.prev = node.next, node.prev
+// link marks node as belonging to the list. It panics if node is already
+// in a list or ring.
+func (dll *DLL[T]) link(node *Node[T]) {
+	if node.linked() {
+		panic("node is already in a list or ring")
+	}
+	if dll.token == nil {
+		dll.token = new(listToken)
+	}
+	node.list = dll.token
+}
+
+// owns reports whether node is in this list.
+func (dll *DLL[T]) owns(node *Node[T]) bool {
+	return node.list != nil && node.list == dll.token
+}
+
+// unlink removes node from the list. The node keeps its links, so that a
+// walk along Next can continue past it.
+func (dll *DLL[T]) unlink(node *Node[T]) {
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		dll.head = node.next
+	}
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		dll.tail = node.prev
+	}
+	node.list = nil
 	dll.size--
+	dll.mods++
 }
 
-// Clear removes all elements from the DLL
-func (dll *DLL[T]) Clear() {
-	dll.head = nil
-	dll.tail = nil
-	dll.size = 0
+// CheckInvariants verifies the DLL's internal structure: the ends are
+// terminated, every next link is mirrored by a prev link, every node
+// belongs to this list, and the recorded size matches the number of linked
+// nodes. It is meant for tests.
+func (dll *DLL[T]) CheckInvariants() error {
+	if (dll.head == nil) != (dll.tail == nil) {
+		return fmt.Errorf("DLL: head is %v but tail is %v", dll.head, dll.tail)
+	}
+	if dll.head != nil && dll.head.prev != nil {
+		return fmt.Errorf("DLL: head has a prev link")
+	}
+	if dll.tail != nil && dll.tail.next != nil {
+		return fmt.Errorf("DLL: tail has a next link")
+	}
+
+	count := 0
+	var prev *Node[T]
+	for node := dll.head; node != nil; node = node.next {
+		if node.prev != prev {
+			return fmt.Errorf("DLL: node %d prev link does not point at node %d", count, count-1)
+		}
+		if !dll.owns(node) {
+			return fmt.Errorf("DLL: node %d belongs to another list", count)
+		}
+		prev = node
+		count++
+		if count > dll.size {
+			return fmt.Errorf("DLL: more than size %d nodes linked", dll.size)
+		}
+	}
+	if prev != dll.tail {
+		return fmt.Errorf("DLL: last linked node is not the tail")
+	}
+	if count != dll.size {
+		return fmt.Errorf("DLL: size is %d but %d nodes are linked", dll.size, count)
+	}
+	return nil
 }
 
-type Iterator[T comparable] struct {
+type Iterator[T any] struct {
 	dll     *DLL[T]
 	current *Node[T]
 }
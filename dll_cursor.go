@@ -0,0 +1,207 @@
+package gocontainers
+
+import "fmt"
+
+// Cursor is a bidirectional position within a DLL.
+//
+// A cursor either points at a node or sits in the gap between two nodes
+// (or before the front / after the back). Remove leaves the cursor in the
+// gap the removed node used to occupy, so Next and Prev keep working and
+// iteration can continue safely after a removal.
+//
+// The list must only be modified through the cursor while it is in use.
+// In debug builds (-tags debug) any other structural change is detected
+// and the next cursor operation panics.
+type Cursor[T any] struct {
+	dll  *DLL[T]
+	node *Node[T] // current node, nil when in a gap
+	prev *Node[T] // node before the gap, only used when node is nil
+	next *Node[T] // node after the gap, only used when node is nil
+	mods int
+}
+
+// Cursor returns a cursor positioned at the front of the DLL.
+// If the DLL is empty, the cursor is not Valid.
+func (dll *DLL[T]) Cursor() *Cursor[T] {
+	c := &Cursor[T]{dll: dll, mods: dll.mods}
+	c.moveTo(dll.head, nil, nil)
+	return c
+}
+
+// CursorBack returns a cursor positioned at the back of the DLL.
+// If the DLL is empty, the cursor is not Valid.
+func (dll *DLL[T]) CursorBack() *Cursor[T] {
+	c := &Cursor[T]{dll: dll, mods: dll.mods}
+	c.moveTo(dll.tail, nil, nil)
+	return c
+}
+
+// moveTo points the cursor at node, or at the gap between prev and next
+// when node is nil.
+func (c *Cursor[T]) moveTo(node, prev, next *Node[T]) {
+	c.node = node
+	if node != nil {
+		c.prev, c.next = nil, nil
+		return
+	}
+	c.prev, c.next = prev, next
+}
+
+func (c *Cursor[T]) check() {
+	if debug && c.mods != c.dll.mods {
+		panic("DLL modified outside of cursor")
+	}
+}
+
+// Valid reports whether the cursor points at a node.
+func (c *Cursor[T]) Valid() bool {
+	c.check()
+	return c.node != nil
+}
+
+// Node returns the node under the cursor, or nil if the cursor is in a gap.
+func (c *Cursor[T]) Node() *Node[T] {
+	c.check()
+	return c.node
+}
+
+// Get returns the element under the cursor.
+// It panics if the cursor is not Valid.
+func (c *Cursor[T]) Get() T {
+	c.check()
+	if c.node == nil {
+		panic("Cursor is not at a node")
+	}
+	return c.node.element
+}
+
+// Next moves the cursor one node towards the back and reports whether it
+// now points at a node. Moving past the back leaves the cursor in the gap
+// after the tail, from where Prev returns to the tail.
+func (c *Cursor[T]) Next() bool {
+	c.check()
+	if c.node == nil {
+		if c.next == nil {
+			return false
+		}
+		c.moveTo(c.next, nil, nil)
+		return true
+	}
+	c.moveTo(c.node.next, c.node, nil)
+	return c.node != nil
+}
+
+// Prev moves the cursor one node towards the front and reports whether it
+// now points at a node. Moving past the front leaves the cursor in the gap
+// before the head, from where Next returns to the head.
+func (c *Cursor[T]) Prev() bool {
+	c.check()
+	if c.node == nil {
+		if c.prev == nil {
+			return false
+		}
+		c.moveTo(c.prev, nil, nil)
+		return true
+	}
+	c.moveTo(c.node.prev, nil, c.node)
+	return c.node != nil
+}
+
+// Seek moves the cursor to the node at index i. Negative indices count
+// from the back, so -1 is the tail. If i is out of range the cursor is
+// left unchanged and Seek returns false.
+func (c *Cursor[T]) Seek(i int) bool {
+	c.check()
+	size := c.dll.size
+	if i < 0 {
+		i += size
+	}
+	if i < 0 || i >= size {
+		return false
+	}
+
+	var node *Node[T]
+	if i < size/2 {
+		node = c.dll.head
+		for ; i > 0; i-- {
+			node = node.next
+		}
+	} else {
+		node = c.dll.tail
+		for i = size - 1 - i; i > 0; i-- {
+			node = node.prev
+		}
+	}
+	c.moveTo(node, nil, nil)
+	return true
+}
+
+// Remove unlinks the node under the cursor and returns it. The cursor is
+// left in the gap between the removed node's neighbours, so a following
+// Next or Prev moves to the neighbour on that side.
+// It panics if the cursor is not Valid.
+func (c *Cursor[T]) Remove() *Node[T] {
+	c.check()
+	node := c.node
+	if node == nil {
+		panic("Cursor is not at a node")
+	}
+	prev, next := node.prev, node.next
+	c.dll.unlink(node)
+	// the cursor keeps its own place, so the returned node can be detached
+	node.prev, node.next = nil, nil
+	c.mods = c.dll.mods
+	c.moveTo(nil, prev, next)
+	return node
+}
+
+// InsertBefore links node into the list just before the cursor. The cursor
+// does not move, so a following Prev visits the inserted node.
+func (c *Cursor[T]) InsertBefore(node *Node[T]) {
+	c.check()
+	if c.node != nil {
+		c.dll.insertBefore(c.node, node)
+	} else {
+		c.dll.insertBefore(c.next, node)
+		c.prev = node
+	}
+	c.mods = c.dll.mods
+}
+
+// InsertAfter links node into the list just after the cursor. The cursor
+// does not move, so a following Next visits the inserted node.
+func (c *Cursor[T]) InsertAfter(node *Node[T]) {
+	c.check()
+	if c.node != nil {
+		c.dll.insertAfter(c.node, node)
+	} else {
+		c.dll.insertAfter(c.prev, node)
+		c.next = node
+	}
+	c.mods = c.dll.mods
+}
+
+// CheckInvariants verifies that the cursor's node is on its list, or that
+// the nodes either side of its gap are adjacent in the list. It is meant
+// for tests, and the list must not have been changed behind the cursor.
+func (c *Cursor[T]) CheckInvariants() error {
+	if c.node != nil {
+		if !c.dll.owns(c.node) {
+			return fmt.Errorf("Cursor: node is not on the list")
+		}
+		return nil
+	}
+	if c.prev == nil && c.next != c.dll.head {
+		return fmt.Errorf("Cursor: gap at the front is not before the head")
+	}
+	if c.next == nil && c.prev != c.dll.tail {
+		return fmt.Errorf("Cursor: gap at the back is not after the tail")
+	}
+	if c.prev != nil && (!c.dll.owns(c.prev) || c.prev.next != c.next) {
+		return fmt.Errorf("Cursor: node before the gap is not linked to the node after it")
+	}
+	if c.next != nil && (!c.dll.owns(c.next) || c.next.prev != c.prev) {
+		return fmt.Errorf("Cursor: node after the gap is not linked to the node before it")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+//go:build debug
+
+package gocontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorDetectsConcurrentModification(t *testing.T) {
+	dll := newIntDLL(1, 2, 3)
+	c := dll.Cursor()
+
+	dll.AddBack(NewNode(4))
+	assert.Panics(t, func() { c.Next() })
+}
+
+func TestCursorOwnModificationsAllowed(t *testing.T) {
+	dll := newIntDLL(1, 2, 3)
+	c := dll.Cursor()
+
+	assert.NotPanics(t, func() {
+		c.Remove()
+		c.Next()
+		c.InsertAfter(NewNode(4))
+		c.Next()
+	})
+}
@@ -0,0 +1,278 @@
+package gocontainers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newIntDLL(values ...int) *DLL[int] {
+	dll := NewDLL[int]()
+	for _, v := range values {
+		dll.AddBack(NewNode(v))
+	}
+	return dll
+}
+
+func dllValues[T any](dll *DLL[T]) []T {
+	values := []T{}
+	for it := dll.Iterator(); it.HasNext(); {
+		values = append(values, it.Next())
+	}
+	return values
+}
+
+func TestCursorForwardBackward(t *testing.T) {
+	dll := newIntDLL(1, 2, 3)
+
+	var forward []int
+	for c := dll.Cursor(); c.Valid(); c.Next() {
+		forward = append(forward, c.Get())
+	}
+	assert.Equal(t, []int{1, 2, 3}, forward)
+
+	var backward []int
+	for c := dll.CursorBack(); c.Valid(); c.Prev() {
+		backward = append(backward, c.Get())
+	}
+	assert.Equal(t, []int{3, 2, 1}, backward)
+}
+
+func TestCursorStepOffEnds(t *testing.T) {
+	dll := newIntDLL(1, 2)
+
+	c := dll.CursorBack()
+	assert.False(t, c.Next())
+	assert.False(t, c.Valid())
+	assert.Nil(t, c.Node())
+	assert.True(t, c.Prev())
+	assert.Equal(t, 2, c.Get())
+
+	c = dll.Cursor()
+	assert.False(t, c.Prev())
+	assert.True(t, c.Next())
+	assert.Equal(t, 1, c.Get())
+}
+
+func TestCursorEmpty(t *testing.T) {
+	dll := NewDLL[int]()
+	c := dll.Cursor()
+	assert.False(t, c.Valid())
+	assert.False(t, c.Next())
+	assert.False(t, c.Prev())
+	assert.False(t, c.Seek(0))
+	assert.Panics(t, func() { c.Get() })
+	assert.Panics(t, func() { c.Remove() })
+}
+
+func TestCursorSeek(t *testing.T) {
+	dll := newIntDLL(0, 1, 2, 3, 4)
+	c := dll.Cursor()
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, c.Seek(i))
+		assert.Equal(t, i, c.Get())
+	}
+	assert.True(t, c.Seek(-1))
+	assert.Equal(t, 4, c.Get())
+	assert.True(t, c.Seek(-5))
+	assert.Equal(t, 0, c.Get())
+
+	assert.False(t, c.Seek(5))
+	assert.False(t, c.Seek(-6))
+	assert.Equal(t, 0, c.Get())
+}
+
+func TestCursorRemoveWhileIterating(t *testing.T) {
+	dll := newIntDLL(1, 2, 3, 4, 5, 6)
+
+	for c := dll.Cursor(); c.Valid(); c.Next() {
+		if c.Get()%2 == 0 {
+			c.Remove()
+		}
+	}
+	assert.Equal(t, []int{1, 3, 5}, dllValues(dll))
+	assert.Equal(t, 3, dll.Size())
+
+	for c := dll.CursorBack(); c.Valid(); c.Prev() {
+		if c.Get() != 3 {
+			c.Remove()
+		}
+	}
+	assert.Equal(t, []int{3}, dllValues(dll))
+	assert.Equal(t, dll.GetFront(), dll.GetBack())
+}
+
+func TestCursorRemoveHeadAndTail(t *testing.T) {
+	dll := newIntDLL(1, 2, 3)
+
+	c := dll.Cursor()
+	removed := c.Remove()
+	assert.Equal(t, 1, removed.Get())
+	assert.Nil(t, removed.Next())
+	assert.Nil(t, removed.Prev())
+	assert.Nil(t, dll.GetFront().Prev())
+	assert.False(t, c.Prev())
+	assert.True(t, c.Next())
+	assert.Equal(t, 2, c.Get())
+
+	c = dll.CursorBack()
+	c.Remove()
+	assert.Nil(t, dll.GetBack().Next())
+	assert.Equal(t, []int{2}, dllValues(dll))
+	assert.True(t, c.Prev())
+	c.Remove()
+	assert.True(t, dll.IsEmpty())
+	assert.Nil(t, dll.GetFront())
+	assert.Nil(t, dll.GetBack())
+}
+
+func TestCursorInsert(t *testing.T) {
+	dll := newIntDLL(2, 4)
+
+	c := dll.Cursor()
+	c.InsertBefore(NewNode(1))
+	c.InsertAfter(NewNode(3))
+	assert.Equal(t, 2, c.Get())
+	assert.Equal(t, []int{1, 2, 3, 4}, dllValues(dll))
+	assert.Equal(t, 1, dll.GetFront().Get())
+
+	c = dll.CursorBack()
+	c.InsertAfter(NewNode(5))
+	assert.Equal(t, 5, dll.GetBack().Get())
+	assert.True(t, c.Next())
+	assert.Equal(t, 5, c.Get())
+	assert.Equal(t, 5, dll.Size())
+}
+
+func TestCursorInsertIntoGap(t *testing.T) {
+	dll := newIntDLL(1, 3)
+
+	c := dll.Cursor()
+	c.Next()
+	c.Remove() // gap between 1 and nil
+	c.InsertBefore(NewNode(2))
+	c.InsertAfter(NewNode(4))
+	assert.Equal(t, []int{1, 2, 4}, dllValues(dll))
+	assert.True(t, c.Next())
+	assert.Equal(t, 4, c.Get())
+
+	empty := NewDLL[int]()
+	c = empty.Cursor()
+	c.InsertBefore(NewNode(1))
+	assert.Equal(t, []int{1}, dllValues(empty))
+	assert.True(t, c.Prev())
+	assert.Equal(t, 1, c.Get())
+}
+
+func TestCursorCheckInvariantsDetectsCorruption(t *testing.T) {
+	dll := NewDLL[int]()
+	for i := 1; i <= 3; i++ {
+		dll.AddBack(NewNode(i))
+	}
+	c := dll.Cursor()
+	c.Next()
+	c.Remove()
+	assert.NoError(t, c.CheckInvariants())
+
+	c.prev = nil
+	assert.Error(t, c.CheckInvariants())
+
+	c.moveTo(NewNode(4), nil, nil)
+	assert.Error(t, c.CheckInvariants())
+}
+
+func FuzzCursor(f *testing.F) {
+	f.Add([]byte{0, 9, 2, 3, 4, 12, 5, 1, 6})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		dll := NewDLL[int]()
+		c := dll.Cursor()
+		// the cursor is at node model[at], or in the gap after at elements
+		var model []int
+		at, onNode := 0, false
+		for i, op := range ops {
+			v := int(op >> 3)
+			switch op % 7 {
+			case 0:
+				c.InsertBefore(NewNode(v))
+				model = slices.Insert(model, at, v)
+				at++
+			case 1:
+				c.InsertAfter(NewNode(v))
+				if onNode {
+					model = slices.Insert(model, at+1, v)
+				} else {
+					model = slices.Insert(model, at, v)
+				}
+			case 2:
+				ok := c.Next()
+				switch {
+				case onNode && at+1 < len(model):
+					at++
+				case onNode:
+					at, onNode = len(model), false
+				case at < len(model):
+					onNode = true
+				}
+				if ok != onNode {
+					t.Fatalf("op %d: Next = %t", i, ok)
+				}
+			case 3:
+				ok := c.Prev()
+				switch {
+				case onNode && at > 0:
+					at--
+				case onNode:
+					onNode = false
+				case at > 0:
+					at, onNode = at-1, true
+				}
+				if ok != onNode {
+					t.Fatalf("op %d: Prev = %t", i, ok)
+				}
+			case 4:
+				if onNode {
+					if got := c.Remove().Get(); got != model[at] {
+						t.Fatalf("op %d: Remove = %d, want %d", i, got, model[at])
+					}
+					model = slices.Delete(model, at, at+1)
+					onNode = false
+				}
+			case 5:
+				n := v%8 - 4
+				idx := n
+				if idx < 0 {
+					idx += len(model)
+				}
+				ok := c.Seek(n)
+				if ok != (idx >= 0 && idx < len(model)) {
+					t.Fatalf("op %d: Seek(%d) = %t with %d elements", i, n, ok, len(model))
+				}
+				if ok {
+					at, onNode = idx, true
+				}
+			case 6:
+				if v%4 == 0 {
+					c = dll.CursorBack()
+					at, onNode = len(model)-1, len(model) > 0
+					if !onNode {
+						at = 0
+					}
+				}
+			}
+
+			if err := c.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			if err := dll.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, append([]int{}, model...), dll.ToSlice(), "after op %d (%d)", i, op)
+			assert.Equal(t, onNode, c.Valid(), "after op %d (%d)", i, op)
+			if onNode {
+				assert.Equal(t, model[at], c.Get(), "after op %d (%d)", i, op)
+			}
+		}
+	})
+}
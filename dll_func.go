@@ -0,0 +1,124 @@
+package gocontainers
+
+// MapDLL returns a new DLL holding f applied to every element of dll, in order.
+func MapDLL[T, U any](dll *DLL[T], f func(T) U) *DLL[U] {
+	result := NewDLL[U]()
+	for node := dll.head; node != nil; node = node.next {
+		result.AddBack(NewNode(f(node.element)))
+	}
+	return result
+}
+
+// Filter returns a new DLL holding the elements of dll for which keep
+// returns true. dll is left unchanged.
+func Filter[T any](dll *DLL[T], keep func(T) bool) *DLL[T] {
+	result := NewDLL[T]()
+	for node := dll.head; node != nil; node = node.next {
+		if keep(node.element) {
+			result.AddBack(NewNode(node.element))
+		}
+	}
+	return result
+}
+
+// FilterInPlace unlinks every node of dll for which keep returns false.
+// The remaining nodes are kept, not reallocated.
+func FilterInPlace[T any](dll *DLL[T], keep func(T) bool) {
+	RemoveIf(dll, func(element T) bool { return !keep(element) })
+}
+
+// RemoveIf unlinks every node of dll for which remove returns true and
+// returns the number of nodes removed.
+func RemoveIf[T any](dll *DLL[T], remove func(T) bool) int {
+	removed := 0
+	for node := dll.head; node != nil; {
+		next := node.next
+		if remove(node.element) {
+			dll.unlink(node)
+			removed++
+		}
+		node = next
+	}
+	return removed
+}
+
+// Reduce folds the elements of dll from front to back into an accumulator,
+// starting from init.
+func Reduce[T, A any](dll *DLL[T], init A, f func(A, T) A) A {
+	acc := init
+	for node := dll.head; node != nil; node = node.next {
+		acc = f(acc, node.element)
+	}
+	return acc
+}
+
+// Any reports whether pred returns true for at least one element of dll.
+func Any[T any](dll *DLL[T], pred func(T) bool) bool {
+	for node := dll.head; node != nil; node = node.next {
+		if pred(node.element) {
+			return true
+		}
+	}
+	return false
+}
+
+// All reports whether pred returns true for every element of dll.
+// It returns true for an empty DLL.
+func All[T any](dll *DLL[T], pred func(T) bool) bool {
+	for node := dll.head; node != nil; node = node.next {
+		if !pred(node.element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of elements of dll for which pred returns true.
+func Count[T any](dll *DLL[T], pred func(T) bool) int {
+	count := 0
+	for node := dll.head; node != nil; node = node.next {
+		if pred(node.element) {
+			count++
+		}
+	}
+	return count
+}
+
+// Partition moves the nodes of dll into two new lists: matched holds the
+// nodes for which pred returns true and rest holds the others, both in
+// their original order. Nodes are relinked, not reallocated, and dll is
+// left empty.
+func Partition[T any](dll *DLL[T], pred func(T) bool) (matched, rest *DLL[T]) {
+	matched, rest = NewDLL[T](), NewDLL[T]()
+	for node := dll.head; node != nil; {
+		next := node.next
+		dll.unlink(node)
+		if pred(node.element) {
+			matched.AddBack(node)
+		} else {
+			rest.AddBack(node)
+		}
+		node = next
+	}
+	return matched, rest
+}
+
+// Unique unlinks every node whose element equals the element of the node
+// before it, so runs of consecutive duplicates collapse to their first node.
+func Unique[T comparable](dll *DLL[T]) {
+	UniqueFunc(dll, func(a, b T) bool { return a == b })
+}
+
+// UniqueFunc is like Unique but compares elements with eq.
+func UniqueFunc[T any](dll *DLL[T], eq func(a, b T) bool) {
+	if dll.head == nil {
+		return
+	}
+	for node := dll.head.next; node != nil; {
+		next := node.next
+		if eq(node.prev.element, node.element) {
+			dll.unlink(node)
+		}
+		node = next
+	}
+}
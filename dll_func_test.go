@@ -0,0 +1,174 @@
+package gocontainers
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isEven(v int) bool { return v%2 == 0 }
+
+func TestMapDLL(t *testing.T) {
+	assert.Equal(t, []string{}, dllValues(MapDLL(newIntDLL(), strconv.Itoa)))
+	assert.Equal(t, []string{"1"}, dllValues(MapDLL(newIntDLL(1), strconv.Itoa)))
+
+	src := newIntDLL(1, 2, 3)
+	mapped := MapDLL(src, strconv.Itoa)
+	assert.Equal(t, []string{"1", "2", "3"}, dllValues(mapped))
+	assert.Equal(t, "3", mapped.GetBack().Get())
+	assert.Equal(t, []int{1, 2, 3}, dllValues(src))
+}
+
+func TestFilter(t *testing.T) {
+	src := newIntDLL(1, 2, 3, 4)
+	filtered := Filter(src, isEven)
+	assert.Equal(t, []int{2, 4}, dllValues(filtered))
+	assert.Equal(t, []int{1, 2, 3, 4}, dllValues(src))
+	assert.Equal(t, 0, Filter(newIntDLL(), isEven).Size())
+}
+
+func TestFilterInPlace(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want []int
+	}{
+		{name: "empty", in: nil, want: []int{}},
+		{name: "single kept", in: []int{2}, want: []int{2}},
+		{name: "single removed", in: []int{1}, want: []int{}},
+		{name: "head removed", in: []int{1, 2, 4}, want: []int{2, 4}},
+		{name: "tail removed", in: []int{2, 4, 5}, want: []int{2, 4}},
+		{name: "head and tail removed", in: []int{1, 2, 3, 4, 5}, want: []int{2, 4}},
+		{name: "all removed", in: []int{1, 3, 5}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dll := newIntDLL(tt.in...)
+			var kept []*Node[int]
+			for node := dll.GetFront(); node != nil; node = node.Next() {
+				if isEven(node.Get()) {
+					kept = append(kept, node)
+				}
+			}
+
+			FilterInPlace(dll, isEven)
+			assert.Equal(t, tt.want, dllValues(dll))
+			assert.Equal(t, len(tt.want), dll.Size())
+			if len(tt.want) == 0 {
+				assert.Nil(t, dll.GetFront())
+				assert.Nil(t, dll.GetBack())
+				return
+			}
+			assert.Nil(t, dll.GetFront().Prev())
+			assert.Nil(t, dll.GetBack().Next())
+			// nodes are relinked, not reallocated
+			i := 0
+			for node := dll.GetFront(); node != nil; node = node.Next() {
+				assert.Same(t, kept[i], node)
+				i++
+			}
+		})
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	dll := newIntDLL(2, 1, 2, 3, 2)
+	assert.Equal(t, 3, RemoveIf(dll, func(v int) bool { return v == 2 }))
+	assert.Equal(t, []int{1, 3}, dllValues(dll))
+	assert.Equal(t, 0, RemoveIf(newIntDLL(), isEven))
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, v int) int { return acc + v }
+	assert.Equal(t, 0, Reduce(newIntDLL(), 0, sum))
+	assert.Equal(t, 7, Reduce(newIntDLL(7), 0, sum))
+	assert.Equal(t, 10, Reduce(newIntDLL(1, 2, 3, 4), 0, sum))
+
+	joined := Reduce(newIntDLL(1, 2, 3), "", func(acc string, v int) string {
+		return acc + strconv.Itoa(v)
+	})
+	assert.Equal(t, "123", joined)
+}
+
+func TestAnyAllCount(t *testing.T) {
+	empty := newIntDLL()
+	assert.False(t, Any(empty, isEven))
+	assert.True(t, All(empty, isEven))
+	assert.Equal(t, 0, Count(empty, isEven))
+
+	single := newIntDLL(2)
+	assert.True(t, Any(single, isEven))
+	assert.True(t, All(single, isEven))
+	assert.Equal(t, 1, Count(single, isEven))
+
+	mixed := newIntDLL(1, 2, 3, 4)
+	assert.True(t, Any(mixed, isEven))
+	assert.False(t, All(mixed, isEven))
+	assert.Equal(t, 2, Count(mixed, isEven))
+	assert.True(t, Any(mixed, func(v int) bool { return v == 4 }))
+}
+
+func TestPartition(t *testing.T) {
+	dll := newIntDLL(1, 2, 3, 4, 5)
+	head, tail := dll.GetFront(), dll.GetBack()
+
+	evens, odds := Partition(dll, isEven)
+	assert.Equal(t, []int{2, 4}, dllValues(evens))
+	assert.Equal(t, []int{1, 3, 5}, dllValues(odds))
+	assert.Same(t, head, odds.GetFront())
+	assert.Same(t, tail, odds.GetBack())
+	assert.Nil(t, evens.GetFront().Prev())
+	assert.Nil(t, evens.GetBack().Next())
+	assert.True(t, dll.IsEmpty())
+	assert.Nil(t, dll.GetFront())
+
+	evens, odds = Partition(newIntDLL(), isEven)
+	assert.True(t, evens.IsEmpty())
+	assert.True(t, odds.IsEmpty())
+
+	evens, odds = Partition(newIntDLL(2), isEven)
+	assert.Equal(t, []int{2}, dllValues(evens))
+	assert.True(t, odds.IsEmpty())
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want []int
+	}{
+		{name: "empty", in: nil, want: []int{}},
+		{name: "single", in: []int{1}, want: []int{1}},
+		{name: "no duplicates", in: []int{1, 2, 3}, want: []int{1, 2, 3}},
+		{name: "duplicate head", in: []int{1, 1, 2}, want: []int{1, 2}},
+		{name: "duplicate tail", in: []int{1, 2, 2, 2}, want: []int{1, 2}},
+		{name: "non-consecutive kept", in: []int{1, 2, 1, 1}, want: []int{1, 2, 1}},
+		{name: "all equal", in: []int{3, 3, 3}, want: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dll := newIntDLL(tt.in...)
+			Unique(dll)
+			assert.Equal(t, tt.want, dllValues(dll))
+			assert.Equal(t, len(tt.want), dll.Size())
+			if len(tt.want) > 0 {
+				assert.Nil(t, dll.GetBack().Next())
+				assert.Equal(t, tt.want[len(tt.want)-1], dll.GetBack().Get())
+			}
+		})
+	}
+}
+
+func TestUniqueFunc(t *testing.T) {
+	dll := NewDLL[[]int]()
+	for _, v := range [][]int{{1}, {1, 2}, {3, 4}, {5}} {
+		dll.AddBack(NewNode(v))
+	}
+	sameLen := func(a, b []int) bool { return len(a) == len(b) }
+
+	UniqueFunc(dll, sameLen)
+	assert.Equal(t, [][]int{{1}, {1, 2}, {5}}, dllValues(dll))
+}
@@ -83,7 +83,7 @@ func TestDLLInt(t *testing.T) {
 				dll.AddFront(NewNode(1))
 				dll.AddFront(NewNode(2))
 				dll.AddFront(NewNode(3))
-				dll.DeleteMatch(2)
+				DeleteMatch(dll, 2)
 			},
 			check: func(t *testing.T, dll *DLL[int]) {
 				if dll.Size() != 2 {
@@ -148,7 +148,7 @@ func TestDLLString(t *testing.T) {
 			setup: func(dll *DLL[string]) {
 				dll.AddFront(NewNode("hello"))
 				dll.AddFront(NewNode("world"))
-				dll.DeleteMatch("hello")
+				DeleteMatch(dll, "hello")
 			},
 			check: func(t *testing.T, dll *DLL[string]) {
 				if dll.Size() != 1 {
@@ -228,3 +228,278 @@ func TestDeleteByNode(t *testing.T) {
 	assert.Equal(t, "are", dll.head.Get())
 	assert.Equal(t, "hello", dll.tail.Get())
 }
+
+func TestDLLNonComparable(t *testing.T) {
+	dll := NewDLL[[]byte]()
+	dll.AddBack(NewNode([]byte("hello")))
+	dll.AddBack(NewNode([]byte("how")))
+	dll.AddBack(NewNode([]byte("are")))
+
+	node := dll.FindFunc(func(b []byte) bool { return string(b) == "how" })
+	assert.NotNil(t, node)
+	assert.Equal(t, []byte("how"), node.Get())
+	assert.Nil(t, dll.FindFunc(func(b []byte) bool { return len(b) == 0 }))
+
+	dll.DeleteMatchFunc(func(b []byte) bool { return len(b) == 3 })
+	assert.Equal(t, 1, dll.Size())
+	assert.Equal(t, []byte("hello"), dll.GetFront().Get())
+	assert.Same(t, dll.GetFront(), dll.GetBack())
+}
+
+func TestFind(t *testing.T) {
+	dll := NewDLL[string]()
+	dll.AddBack(NewNode("a"))
+	dll.AddBack(NewNode("b"))
+	dll.AddBack(NewNode("b"))
+
+	assert.Same(t, dll.GetFront().Next(), Find(dll, "b"))
+	assert.Nil(t, Find(dll, "c"))
+	assert.Nil(t, Find(NewDLL[string](), "a"))
+}
+
+func TestDeleteNodeKeepsLinksSymmetric(t *testing.T) {
+	dll := NewDLL[int]()
+	nodes := []*Node[int]{NewNode(1), NewNode(2), NewNode(3), NewNode(4)}
+	for _, n := range nodes {
+		dll.AddBack(n)
+	}
+
+	dll.DeleteNode(nodes[0])
+	assert.NoError(t, dll.CheckInvariants())
+	assert.Nil(t, dll.GetFront().Prev())
+	assert.Same(t, nodes[2], dll.GetBack().Prev())
+
+	dll.DeleteNode(nodes[3])
+	assert.NoError(t, dll.CheckInvariants())
+	assert.Nil(t, dll.GetBack().Next())
+	assert.Same(t, nodes[2], dll.GetFront().Next())
+	assert.Equal(t, []int{2, 3}, dll.ToSlice())
+}
+
+func TestDeleteNodeIgnoresForeignNode(t *testing.T) {
+	dll := NewDLL[int]()
+	dll.AddBack(NewNode(1))
+	dll.AddBack(NewNode(2))
+	other := NewDLL[int]()
+	foreign := NewNode(3)
+	other.AddBack(foreign)
+
+	dll.DeleteNode(NewNode(9))
+	dll.DeleteNode(foreign)
+	assert.NoError(t, dll.CheckInvariants())
+	assert.NoError(t, other.CheckInvariants())
+	assert.Equal(t, []int{1, 2}, dll.ToSlice())
+	assert.Equal(t, []int{3}, other.ToSlice())
+}
+
+func TestDeleteNodeTwice(t *testing.T) {
+	dll := NewDLL[int]()
+	n := NewNode(1)
+	dll.AddBack(n)
+	dll.AddBack(NewNode(2))
+
+	dll.DeleteNode(n)
+	dll.DeleteNode(n)
+	assert.NoError(t, dll.CheckInvariants())
+	assert.Equal(t, []int{2}, dll.ToSlice())
+
+	// nodes unlinked by Clear are stale too
+	last := dll.GetFront()
+	dll.Clear()
+	dll.AddBack(NewNode(3))
+	dll.DeleteNode(last)
+	assert.NoError(t, dll.CheckInvariants())
+	assert.Equal(t, []int{3}, dll.ToSlice())
+}
+
+func TestDeleteWhileIterating(t *testing.T) {
+	dll := NewDLL[int]()
+	for i := 1; i <= 5; i++ {
+		dll.AddBack(NewNode(i))
+	}
+	for n := dll.GetFront(); n != nil; n = n.Next() {
+		if n.Get()%2 == 0 {
+			dll.DeleteNode(n)
+		}
+	}
+	assert.NoError(t, dll.CheckInvariants())
+	assert.Equal(t, []int{1, 3, 5}, dll.ToSlice())
+
+	for n := dll.GetBack(); n != nil; n = n.Prev() {
+		if n.Get() != 3 {
+			dll.DeleteNode(n)
+		}
+	}
+	assert.NoError(t, dll.CheckInvariants())
+	assert.Equal(t, []int{3}, dll.ToSlice())
+}
+
+func TestAddLinkedNode(t *testing.T) {
+	dll := NewDLL[int]()
+	n := NewNode(1)
+	dll.AddBack(n)
+	assert.Panics(t, func() { dll.AddBack(n) })
+	assert.Panics(t, func() { NewDLL[int]().AddFront(n) })
+
+	// deleted and cleared nodes may be added again
+	dll.DeleteNode(n)
+	other := NewDLL[int]()
+	other.AddBack(n)
+	other.AddBack(NewNode(2))
+	other.Clear()
+	dll.AddFront(n)
+	dll.AddBack(NewNode(3))
+	assert.NoError(t, dll.CheckInvariants())
+	assert.NoError(t, other.CheckInvariants())
+	assert.Equal(t, []int{1, 3}, dll.ToSlice())
+	assert.True(t, other.IsEmpty())
+}
+
+func TestDLLCheckInvariantsDetectsCorruption(t *testing.T) {
+	dll := NewDLL[int]()
+	dll.AddBack(NewNode(1))
+	dll.AddBack(NewNode(2))
+	assert.NoError(t, dll.CheckInvariants())
+
+	dll.size = 3
+	assert.Error(t, dll.CheckInvariants())
+	dll.size = 2
+
+	dll.tail.prev = nil
+	assert.Error(t, dll.CheckInvariants())
+}
+
+// FuzzDLL applies a random sequence of operations, one per input byte, to a
+// DLL and a slice model and checks that they agree after every step.
+// Deleting nodes that were already removed, or that belong to another list,
+// must leave the list unchanged.
+func FuzzDLL(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3, 4, 5})
+	f.Add([]byte{1, 1, 1, 0x14, 0x24, 3})
+	f.Add([]byte{1, 1, 4, 7, 0xf, 6, 7})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		dll := NewDLL[int]()
+		var nodes []*Node[int] // mirrors the list order
+		var stale []*Node[int] // nodes removed from dll
+		other := NewDLL[int]()
+		for i := range 3 {
+			other.AddBack(NewNode(-i))
+		}
+		for i, op := range ops {
+			v := int(op >> 3)
+			switch op % 8 {
+			case 0:
+				n := NewNode(v)
+				dll.AddFront(n)
+				nodes = append([]*Node[int]{n}, nodes...)
+			case 1:
+				n := NewNode(v)
+				dll.AddBack(n)
+				nodes = append(nodes, n)
+			case 2:
+				dll.RemoveFront()
+				if len(nodes) > 0 {
+					stale = append(stale, nodes[0])
+					nodes = nodes[1:]
+				}
+			case 3:
+				dll.RemoveBack()
+				if len(nodes) > 0 {
+					stale = append(stale, nodes[len(nodes)-1])
+					nodes = nodes[:len(nodes)-1]
+				}
+			case 4:
+				if len(nodes) > 0 {
+					j := v % len(nodes)
+					dll.DeleteNode(nodes[j])
+					stale = append(stale, nodes[j])
+					nodes = append(nodes[:j:j], nodes[j+1:]...)
+				}
+			case 5:
+				DeleteMatch(dll, v)
+				kept := nodes[:0:0]
+				for _, n := range nodes {
+					if n.Get() != v {
+						kept = append(kept, n)
+					} else {
+						stale = append(stale, n)
+					}
+				}
+				nodes = kept
+			case 6:
+				if v%4 == 0 {
+					dll.Clear()
+					stale = append(stale, nodes...)
+					nodes = nil
+				}
+			case 7:
+				// a stale node if there is one, otherwise a foreign one
+				if len(stale) > 0 && v%2 == 0 {
+					dll.DeleteNode(stale[v%len(stale)])
+				} else {
+					dll.DeleteNode(other.GetFront())
+				}
+			}
+
+			if err := dll.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			if err := other.CheckInvariants(); err != nil || other.Size() != 3 {
+				t.Fatalf("after op %d (%d): other list changed: %v", i, op, err)
+			}
+			want := make([]int, len(nodes))
+			for j, n := range nodes {
+				want[j] = n.Get()
+			}
+			assert.Equal(t, want, dll.ToSlice(), "after op %d (%d)", i, op)
+		}
+	})
+}
+
+func benchDLLAddRemove[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		dll := NewDLL[T]()
+		for _, v := range values {
+			dll.AddBack(NewNode(v))
+		}
+		for !dll.IsEmpty() {
+			dll.RemoveFront()
+		}
+	}
+}
+
+func benchDLLIterate[T any](b *testing.B, values []T) {
+	dll := NewDLL[T]()
+	for _, v := range values {
+		dll.AddBack(NewNode(v))
+	}
+	for b.Loop() {
+		for it := dll.Iterator(); it.HasNext(); {
+			it.Next()
+		}
+	}
+}
+
+// benchDLLFind looks up the last element, the worst case for a linear scan.
+func benchDLLFind[T comparable](b *testing.B, values []T) {
+	dll := NewDLL[T]()
+	for _, v := range values {
+		dll.AddBack(NewNode(v))
+	}
+	target := values[len(values)-1]
+	for b.Loop() {
+		Find(dll, target)
+	}
+}
+
+func BenchmarkDLLAddRemove(b *testing.B) {
+	runBench(b, benchDLLAddRemove[int], benchDLLAddRemove[string])
+}
+
+func BenchmarkDLLIterate(b *testing.B) {
+	runBench(b, benchDLLIterate[int], benchDLLIterate[string])
+}
+
+func BenchmarkDLLFind(b *testing.B) {
+	runBench(b, benchDLLFind[int], benchDLLFind[string])
+}
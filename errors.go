@@ -0,0 +1,30 @@
+package gocontainers
+
+import "errors"
+
+var (
+	// ErrClosed is returned by operations on a container that has been closed.
+	ErrClosed = errors.New("gocontainers: container is closed")
+
+	// ErrFull is returned when adding to a bounded container that is full
+	// and whose overflow policy is OverflowReject.
+	ErrFull = errors.New("gocontainers: container is full")
+
+	// ErrDropped is returned when adding to a bounded container that is
+	// full and whose overflow policy is OverflowDropLowest, if the new
+	// element has the lowest priority and so is the one discarded.
+	ErrDropped = errors.New("gocontainers: element was dropped")
+
+	// ErrIncompatible is returned when combining two probabilistic
+	// containers whose sizes or parameters differ.
+	ErrIncompatible = errors.New("gocontainers: containers are incompatible")
+
+	// ErrNoHasher is returned when unmarshaling into the zero value of a
+	// container that needs a Hasher. Create the container with one of its
+	// constructors first.
+	ErrNoHasher = errors.New("gocontainers: container has no hasher")
+
+	// ErrInvalidEncoding is returned when unmarshaling data that was not
+	// produced by the matching MarshalBinary, or that is truncated.
+	ErrInvalidEncoding = errors.New("gocontainers: invalid encoding")
+)
@@ -0,0 +1,44 @@
+package gocontainers
+
+import "hash/maphash"
+
+// Hasher maps a value to a 64-bit hash. Equal values must produce equal
+// hashes, and the bits should be well mixed since containers use both the
+// low and the high bits.
+//
+// Containers that are merged, or that exchange data with MarshalBinary,
+// must use the same Hasher. They record a fingerprint of their Hasher,
+// which is also part of the encoding, and return ErrIncompatible when
+// asked to combine with or decode data from a container hashed
+// differently. The plain constructors share one hash/maphash seed per
+// process, so their encodings can only be decoded by the same process; use
+// the WithHasher constructors and a deterministic Hasher to persist them.
+type Hasher[T comparable] func(T) uint64
+
+// defaultSeed seeds the Hasher of every container built by a constructor
+// that takes none, so that they all agree within a process.
+var defaultSeed = maphash.MakeSeed()
+
+func defaultHasher[T comparable]() Hasher[T] {
+	return func(v T) uint64 {
+		return maphash.Comparable(defaultSeed, v)
+	}
+}
+
+// hasherID fingerprints hash by hashing the zero value of T. Hashers with
+// different seeds or mixing functions almost surely disagree on it, but
+// hashers that agree on the zero value cannot be told apart.
+func hasherID[T comparable](hash Hasher[T]) uint64 {
+	var zero T
+	return hash(zero)
+}
+
+// NewMaphashHasher returns a Hasher for any comparable type backed by
+// hash/maphash. Each call picks a new random seed, so hashes differ between
+// hashers and between processes.
+func NewMaphashHasher[T comparable]() Hasher[T] {
+	seed := maphash.MakeSeed()
+	return func(v T) uint64 {
+		return maphash.Comparable(seed, v)
+	}
+}
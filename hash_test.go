@@ -0,0 +1,50 @@
+package gocontainers
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mixHasher is a deterministic Hasher for ints (the splitmix64 finalizer),
+// for tests whose results must not depend on a random seed.
+func mixHasher(v int) uint64 {
+	x := uint64(v) + 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+func TestMaphashHasher(t *testing.T) {
+	h := NewMaphashHasher[string]()
+	assert.Equal(t, h("a"), h("a"))
+	assert.NotEqual(t, h("a"), h("b"))
+
+	type point struct{ x, y int }
+	hp := NewMaphashHasher[point]()
+	assert.Equal(t, hp(point{1, 2}), hp(point{1, 2}))
+	assert.NotEqual(t, hp(point{1, 2}), hp(point{2, 1}))
+}
+
+// TestUnmarshalWithoutHasher checks that the zero value of every container
+// that needs a Hasher refuses to decode rather than panic later.
+func TestUnmarshalWithoutHasher(t *testing.T) {
+	impls := map[string]struct {
+		zero    encoding.BinaryUnmarshaler
+		encoded encoding.BinaryMarshaler
+	}{
+		"BloomFilter":         {new(BloomFilter[int]), NewBloomFilter[int](10, 0.01)},
+		"CountingBloomFilter": {new(CountingBloomFilter[int]), NewCountingBloomFilter[int](10, 0.01)},
+		"CuckooFilter":        {new(CuckooFilter[int]), NewCuckooFilter[int](10)},
+		"HyperLogLog":         {new(HyperLogLog[int]), NewHyperLogLog[int](12)},
+	}
+	for name, impl := range impls {
+		t.Run(name, func(t *testing.T) {
+			data, err := impl.encoded.MarshalBinary()
+			assert.NoError(t, err)
+			assert.True(t, errors.Is(impl.zero.UnmarshalBinary(data), ErrNoHasher))
+		})
+	}
+}
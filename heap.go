@@ -43,6 +43,32 @@ func (h *Heap[T]) Len() int {
 	return len(h.data)
 }
 
+// Size returns the number of items in the heap.
+func (h *Heap[T]) Size() int {
+	return len(h.data)
+}
+
+func (h *Heap[T]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+// Clear removes all items from the heap.
+func (h *Heap[T]) Clear() {
+	for _, item := range h.data {
+		item.index = -1
+	}
+	h.data = []*Item[T]{}
+}
+
+// ToSlice returns the values in heap order, which is not sorted order.
+func (h *Heap[T]) ToSlice() []T {
+	result := make([]T, len(h.data))
+	for i, item := range h.data {
+		result[i] = item.val
+	}
+	return result
+}
+
 // Less compares two elements by their priority using the comparator.
 func (h *Heap[T]) Less(i, j int) bool {
 	return h.comparator(h.data[i].val, h.data[j].val)
@@ -109,8 +135,23 @@ func (h *Heap[T]) Peek() (*Item[T], bool) {
 	return h.data[0], true
 }
 
+// ItemExists reports whether item is currently in the heap.
 func (h *Heap[T]) ItemExists(item *Item[T]) bool {
-	return item.index > 0
+	return item.index >= 0 && item.index < len(h.data) && h.data[item.index] == item
+}
+
+// CheckInvariants verifies the heap property and that every item records
+// its own position. It is meant for tests.
+func (h *Heap[T]) CheckInvariants() error {
+	for i, item := range h.data {
+		if item.index != i {
+			return fmt.Errorf("Heap: item at position %d records index %d", i, item.index)
+		}
+		if i > 0 && h.Less(i, (i-1)/2) {
+			return fmt.Errorf("Heap: item at position %d has higher priority than its parent", i)
+		}
+	}
+	return nil
 }
 
 // String returns a string representation of the heap (for debugging).
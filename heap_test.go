@@ -1,7 +1,9 @@
 package gocontainers
 
 import (
+	"cmp"
 	"github.com/stretchr/testify/assert"
+	"slices"
 	"testing"
 )
 
@@ -122,3 +124,154 @@ func TestHeapEmptyPeekPop(t *testing.T) {
 	assert.Nil(t, val)
 	assert.Equal(t, 0, h.Len())
 }
+
+func TestHeapClear(t *testing.T) {
+	h := NewHeap(func(a, b int) bool { return a > b })
+	item := NewItem(1)
+	h.PushItem(item)
+	h.PushItem(NewItem(2))
+	assert.Equal(t, 2, h.Size())
+	assert.False(t, h.IsEmpty())
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	assert.Equal(t, 0, h.Len())
+	assert.Equal(t, -1, item.index)
+}
+
+func TestHeapItemExists(t *testing.T) {
+	h := NewHeap(func(a, b int) bool { return a < b })
+	root := NewItem(1)
+	child := NewItem(2)
+	assert.False(t, h.ItemExists(root))
+
+	h.PushItem(root)
+	h.PushItem(child)
+	assert.True(t, h.ItemExists(root), "item at index 0 exists")
+	assert.True(t, h.ItemExists(child))
+
+	other := NewHeap(func(a, b int) bool { return a < b })
+	assert.False(t, other.ItemExists(child), "item belongs to a different heap")
+
+	h.PopItem()
+	assert.False(t, h.ItemExists(root))
+	h.RemoveItem(child)
+	assert.False(t, h.ItemExists(child))
+}
+
+func TestHeapCheckInvariantsDetectsCorruption(t *testing.T) {
+	h := NewHeap(func(a, b int) bool { return a < b })
+	for _, v := range []int{3, 1, 2} {
+		h.PushItem(NewItem(v))
+	}
+	assert.NoError(t, h.CheckInvariants())
+
+	h.data[0].val = 10
+	assert.Error(t, h.CheckInvariants())
+	h.data[0].val = 1
+
+	h.data[1].index = 2
+	assert.Error(t, h.CheckInvariants())
+}
+
+// FuzzHeap applies a random sequence of operations, one per input byte, to
+// a min-heap and a slice of live items and checks that they agree.
+func FuzzHeap(f *testing.F) {
+	f.Add([]byte{0, 8, 16, 1, 2, 3})
+	f.Add([]byte{0, 0, 0, 0x1b, 0x0a, 1, 1})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		h := NewHeap(func(a, b int) bool { return a < b })
+		var live []*Item[int]
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 4 {
+			case 0:
+				item := NewItem(v)
+				h.PushItem(item)
+				live = append(live, item)
+			case 1:
+				if len(live) == 0 {
+					break
+				}
+				want := live[0].Get()
+				for _, item := range live {
+					want = min(want, item.Get())
+				}
+				item := h.PopItem()
+				assert.Equal(t, want, item.Get(), "after op %d", i)
+				assert.False(t, h.ItemExists(item))
+				live = slices.DeleteFunc(live, func(it *Item[int]) bool { return it == item })
+			case 2:
+				if len(live) > 0 {
+					item := live[v%len(live)]
+					item.Update(int(op))
+					h.Update(item)
+				}
+			case 3:
+				if len(live) > 0 {
+					j := v % len(live)
+					h.RemoveItem(live[j])
+					assert.False(t, h.ItemExists(live[j]))
+					live = slices.Delete(live, j, j+1)
+				}
+			}
+
+			if err := h.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(live), h.Size())
+			for _, item := range live {
+				assert.True(t, h.ItemExists(item))
+			}
+		}
+	})
+}
+
+func benchHeapPushPop[T cmp.Ordered](b *testing.B, values []T) {
+	for b.Loop() {
+		h := NewHeap(func(a, b T) bool { return a < b })
+		for _, v := range values {
+			h.PushItem(NewItem(v))
+		}
+		for h.Len() > 0 {
+			h.PopItem()
+		}
+	}
+}
+
+func benchHeapIterate[T cmp.Ordered](b *testing.B, values []T) {
+	h := NewHeap(func(a, b T) bool { return a < b })
+	for _, v := range values {
+		h.PushItem(NewItem(v))
+	}
+	for b.Loop() {
+		for range h.data {
+		}
+	}
+}
+
+func benchHeapContains[T cmp.Ordered](b *testing.B, values []T) {
+	h := NewHeap(func(a, b T) bool { return a < b })
+	items := make([]*Item[T], len(values))
+	for i, v := range values {
+		items[i] = NewItem(v)
+		h.PushItem(items[i])
+	}
+	for b.Loop() {
+		for _, item := range items {
+			h.ItemExists(item)
+		}
+	}
+}
+
+func BenchmarkHeapPushPop(b *testing.B) {
+	runBench(b, benchHeapPushPop[int], benchHeapPushPop[string])
+}
+
+func BenchmarkHeapIterate(b *testing.B) {
+	runBench(b, benchHeapIterate[int], benchHeapIterate[string])
+}
+
+func BenchmarkHeapContains(b *testing.B) {
+	runBench(b, benchHeapContains[int], benchHeapContains[string])
+}
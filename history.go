@@ -0,0 +1,270 @@
+package gocontainers
+
+import "fmt"
+
+// History is an undo/redo manager. Each recorded action is an opaque value
+// of type T, typically a command or an inverse operation; History only
+// decides which actions to hand back to the caller for reverting or
+// reapplying.
+//
+// Doing a new action clears the redo history. Consecutive actions can be
+// merged into one undo step with SetMerge, several actions can be grouped
+// into one step with Begin and Commit, and named checkpoints can be undone
+// to in one call with UndoTo.
+type History[T any] struct {
+	undo        *Stack[*historyEntry[T]]
+	redo        *Stack[*historyEntry[T]]
+	maxDepth    int
+	merge       func(prev, next T) (T, bool)
+	mergeable   bool // whether the next Do may merge into the top entry
+	group       *historyEntry[T]
+	groupDepth  int
+	seq         uint64
+	base        uint64 // seq of the state beneath the oldest undo step
+	checkpoints map[string]uint64
+}
+
+// historyEntry is one undo step, holding its actions in the order they
+// were done.
+type historyEntry[T any] struct {
+	actions []T
+	seq     uint64
+}
+
+// NewHistory creates a new History that keeps at most maxDepth undo steps,
+// discarding the oldest ones first. maxDepth <= 0 means unlimited.
+func NewHistory[T any](maxDepth int) *History[T] {
+	return &History[T]{
+		undo:        NewStack[*historyEntry[T]](),
+		redo:        NewStack[*historyEntry[T]](),
+		maxDepth:    maxDepth,
+		checkpoints: make(map[string]uint64),
+	}
+}
+
+// SetMerge sets the function used to merge consecutive actions, such as
+// keystrokes typed into the same word. merge returns the combined action
+// and true, or false if the two actions must stay separate undo steps.
+func (h *History[T]) SetMerge(merge func(prev, next T) (T, bool)) {
+	h.merge = merge
+}
+
+// Do records action as done and clears the redo history. Inside a group,
+// action is added to the group instead.
+func (h *History[T]) Do(action T) {
+	if h.group != nil {
+		h.group.actions = h.appendAction(h.group.actions, action)
+		return
+	}
+
+	h.redo.Clear()
+	if h.mergeable && h.merge != nil && !h.undo.IsEmpty() {
+		top := h.undo.Peek()
+		if len(top.actions) == 1 {
+			if merged, ok := h.merge(top.actions[0], action); ok {
+				top.actions[0] = merged
+				return
+			}
+		}
+	}
+	h.push([]T{action})
+	h.mergeable = true
+}
+
+func (h *History[T]) appendAction(actions []T, action T) []T {
+	if n := len(actions); n > 0 && h.merge != nil {
+		if merged, ok := h.merge(actions[n-1], action); ok {
+			actions[n-1] = merged
+			return actions
+		}
+	}
+	return append(actions, action)
+}
+
+func (h *History[T]) push(actions []T) {
+	h.seq++
+	h.undo.Push(&historyEntry[T]{actions: actions, seq: h.seq})
+	for h.maxDepth > 0 && h.undo.Size() > h.maxDepth {
+		h.base = h.undo.RemoveBottom().seq
+	}
+}
+
+// Begin starts a group: every action done until the matching Commit
+// becomes a single undo step. Groups may nest; only the outermost Commit
+// records the step.
+func (h *History[T]) Begin() {
+	if h.groupDepth == 0 {
+		h.group = &historyEntry[T]{}
+	}
+	h.groupDepth++
+}
+
+// Commit ends the innermost group. When the outermost group ends, its
+// actions are recorded as one undo step and the redo history is cleared.
+// An empty group records nothing. It panics if no group is open.
+func (h *History[T]) Commit() {
+	if h.groupDepth == 0 {
+		panic("Commit without Begin")
+	}
+	h.groupDepth--
+	if h.groupDepth > 0 {
+		return
+	}
+	actions := h.group.actions
+	h.group = nil
+	if len(actions) > 0 {
+		h.redo.Clear()
+		h.push(actions)
+		h.mergeable = false
+	}
+}
+
+// Rollback abandons every open group and returns their actions in the
+// order they should be reverted. Nothing is recorded.
+func (h *History[T]) Rollback() []T {
+	if h.group == nil {
+		return nil
+	}
+	actions := reversed(h.group.actions)
+	h.group = nil
+	h.groupDepth = 0
+	return actions
+}
+
+// Undo moves the most recent step to the redo history and returns its
+// actions in the order they should be reverted. It returns false if there
+// is nothing to undo or a group is open.
+func (h *History[T]) Undo() ([]T, bool) {
+	if !h.CanUndo() {
+		return nil, false
+	}
+	entry := h.undo.Pop()
+	h.redo.Push(entry)
+	h.mergeable = false
+	return reversed(entry.actions), true
+}
+
+// Redo moves the most recently undone step back to the undo history and
+// returns its actions in the order they should be reapplied. It returns
+// false if there is nothing to redo or a group is open.
+func (h *History[T]) Redo() ([]T, bool) {
+	if !h.CanRedo() {
+		return nil, false
+	}
+	entry := h.redo.Pop()
+	h.undo.Push(entry)
+	h.mergeable = false
+	actions := make([]T, len(entry.actions))
+	copy(actions, entry.actions)
+	return actions, true
+}
+
+func (h *History[T]) CanUndo() bool {
+	return h.group == nil && !h.undo.IsEmpty()
+}
+
+func (h *History[T]) CanRedo() bool {
+	return h.group == nil && !h.redo.IsEmpty()
+}
+
+// Checkpoint marks the current state under name, replacing any earlier
+// checkpoint with the same name. The next action will not be merged into
+// the step before the checkpoint.
+func (h *History[T]) Checkpoint(name string) {
+	seq := h.base
+	if !h.undo.IsEmpty() {
+		seq = h.undo.Peek().seq
+	}
+	h.checkpoints[name] = seq
+	h.mergeable = false
+}
+
+// UndoTo undoes every step done after the checkpoint name and returns their
+// actions in the order they should be reverted. It returns false, undoing
+// nothing, if the checkpoint does not exist, a group is open, or the
+// checkpointed state is no longer in the undo history.
+func (h *History[T]) UndoTo(name string) ([]T, bool) {
+	seq, ok := h.checkpoints[name]
+	if !ok || h.group != nil || !h.reachable(seq) {
+		return nil, false
+	}
+	var actions []T
+	for !h.undo.IsEmpty() && h.undo.Peek().seq > seq {
+		undone, _ := h.Undo()
+		actions = append(actions, undone...)
+	}
+	return actions, true
+}
+
+// reachable reports whether undoing can return to the state right after
+// the step with the given seq.
+func (h *History[T]) reachable(seq uint64) bool {
+	if seq == h.base {
+		return true
+	}
+	for _, entry := range h.undo.elements {
+		if entry.seq == seq {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of steps held, counting both those that can be
+// undone and those that can be redone.
+func (h *History[T]) Size() int {
+	return h.undo.Size() + h.redo.Size()
+}
+
+func (h *History[T]) IsEmpty() bool {
+	return h.Size() == 0
+}
+
+// Clear discards the undo and redo histories, open groups and checkpoints.
+func (h *History[T]) Clear() {
+	h.undo.Clear()
+	h.redo.Clear()
+	h.group = nil
+	h.groupDepth = 0
+	h.mergeable = false
+	h.base = h.seq
+	h.checkpoints = make(map[string]uint64)
+}
+
+func reversed[T any](actions []T) []T {
+	result := make([]T, len(actions))
+	for i, action := range actions {
+		result[len(actions)-1-i] = action
+	}
+	return result
+}
+
+// CheckInvariants verifies that undo steps are in the order they were done,
+// that every redo step is newer than every undo step and is undone in
+// order, that no step is empty, and that the depth limit and group state
+// are consistent. It is meant for tests.
+func (h *History[T]) CheckInvariants() error {
+	if h.maxDepth > 0 && h.undo.Size() > h.maxDepth {
+		return fmt.Errorf("History: %d undo steps exceed the limit of %d", h.undo.Size(), h.maxDepth)
+	}
+	if (h.group != nil) != (h.groupDepth > 0) {
+		return fmt.Errorf("History: group depth %d with group set %v", h.groupDepth, h.group != nil)
+	}
+	// undo from bottom to top, then redo from top to bottom, is the order
+	// the steps were done
+	steps := append([]*historyEntry[T](nil), h.undo.elements...)
+	for i := len(h.redo.elements) - 1; i >= 0; i-- {
+		steps = append(steps, h.redo.elements[i])
+	}
+	last := h.base
+	for i, entry := range steps {
+		if len(entry.actions) == 0 {
+			return fmt.Errorf("History: step %d has no actions", i)
+		}
+		if entry.seq <= last || entry.seq > h.seq {
+			return fmt.Errorf("History: step %d has seq %d after %d, latest %d", i, entry.seq, last, h.seq)
+		}
+		last = entry.seq
+	}
+	return nil
+}
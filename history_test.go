@@ -0,0 +1,307 @@
+package gocontainers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	h := NewHistory[string](0)
+	assert.False(t, h.CanUndo())
+	assert.False(t, h.CanRedo())
+
+	h.Do("a")
+	h.Do("b")
+	assert.Equal(t, 2, h.Size())
+
+	actions, ok := h.Undo()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"b"}, actions)
+	assert.True(t, h.CanRedo())
+
+	actions, ok = h.Redo()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"b"}, actions)
+	assert.False(t, h.CanRedo())
+
+	h.Undo()
+	h.Undo()
+	_, ok = h.Undo()
+	assert.False(t, ok)
+	assert.False(t, h.CanUndo())
+	assert.Equal(t, 2, h.Size(), "both steps can still be redone")
+	assert.False(t, h.IsEmpty())
+
+	h.Do("c")
+	assert.Equal(t, 1, h.Size())
+}
+
+func TestHistoryDoClearsRedo(t *testing.T) {
+	h := NewHistory[string](0)
+	h.Do("a")
+	h.Do("b")
+	h.Undo()
+	assert.True(t, h.CanRedo())
+
+	h.Do("c")
+	assert.False(t, h.CanRedo())
+	_, ok := h.Redo()
+	assert.False(t, ok)
+
+	actions, _ := h.Undo()
+	assert.Equal(t, []string{"c"}, actions)
+}
+
+func TestHistoryMaxDepth(t *testing.T) {
+	h := NewHistory[int](3)
+	for i := 1; i <= 5; i++ {
+		h.Do(i)
+	}
+	assert.Equal(t, 3, h.Size())
+
+	var undone []int
+	for h.CanUndo() {
+		actions, _ := h.Undo()
+		undone = append(undone, actions...)
+	}
+	assert.Equal(t, []int{5, 4, 3}, undone)
+}
+
+func TestHistoryMerge(t *testing.T) {
+	h := NewHistory[string](0)
+	// merge consecutive keystrokes until a space is typed
+	h.SetMerge(func(prev, next string) (string, bool) {
+		if strings.HasSuffix(prev, " ") {
+			return "", false
+		}
+		return prev + next, true
+	})
+
+	for _, key := range []string{"h", "i", " ", "y", "o"} {
+		h.Do(key)
+	}
+	assert.Equal(t, 2, h.Size())
+
+	actions, _ := h.Undo()
+	assert.Equal(t, []string{"yo"}, actions)
+
+	// no merging into a step that was undone back to
+	h.Do("x")
+	assert.Equal(t, 2, h.Size())
+	actions, _ = h.Undo()
+	assert.Equal(t, []string{"x"}, actions)
+	actions, _ = h.Undo()
+	assert.Equal(t, []string{"hi "}, actions)
+}
+
+func TestHistoryGroups(t *testing.T) {
+	h := NewHistory[string](0)
+	h.Do("a")
+	h.Begin()
+	h.Do("b")
+	h.Begin()
+	h.Do("c")
+	h.Commit()
+	assert.False(t, h.CanUndo(), "no undo while a group is open")
+	h.Do("d")
+	h.Commit()
+
+	assert.Equal(t, 2, h.Size())
+	actions, _ := h.Undo()
+	assert.Equal(t, []string{"d", "c", "b"}, actions)
+	actions, _ = h.Redo()
+	assert.Equal(t, []string{"b", "c", "d"}, actions)
+
+	h.Begin()
+	h.Commit()
+	assert.Equal(t, 2, h.Size(), "empty group records nothing")
+
+	assert.Panics(t, func() { h.Commit() })
+}
+
+func TestHistoryRollback(t *testing.T) {
+	h := NewHistory[string](0)
+	h.Do("a")
+	h.Begin()
+	h.Do("b")
+	h.Do("c")
+
+	assert.Equal(t, []string{"c", "b"}, h.Rollback())
+	assert.Equal(t, 1, h.Size())
+	assert.True(t, h.CanUndo())
+	assert.Nil(t, h.Rollback())
+}
+
+func TestHistoryCheckpoints(t *testing.T) {
+	h := NewHistory[int](0)
+	h.Checkpoint("start")
+	h.Do(1)
+	h.Do(2)
+	h.Checkpoint("saved")
+	h.Do(3)
+	h.Do(4)
+
+	actions, ok := h.UndoTo("saved")
+	assert.True(t, ok)
+	assert.Equal(t, []int{4, 3}, actions)
+	assert.Equal(t, 4, h.Size(), "the undone steps can be redone")
+
+	actions, ok = h.UndoTo("saved")
+	assert.True(t, ok)
+	assert.Empty(t, actions)
+
+	actions, ok = h.UndoTo("start")
+	assert.True(t, ok)
+	assert.Equal(t, []int{2, 1}, actions)
+
+	_, ok = h.UndoTo("missing")
+	assert.False(t, ok)
+}
+
+func TestHistoryCheckpointUnreachable(t *testing.T) {
+	h := NewHistory[int](2)
+	h.Checkpoint("start")
+	h.Do(1)
+	h.Checkpoint("one")
+	h.Do(2)
+	h.Do(3) // evicts 1, so "start" is gone but "one" is the new base
+
+	_, ok := h.UndoTo("start")
+	assert.False(t, ok)
+	assert.Equal(t, 2, h.Size())
+
+	actions, ok := h.UndoTo("one")
+	assert.True(t, ok)
+	assert.Equal(t, []int{3, 2}, actions)
+
+	// a checkpointed step that was undone and replaced is unreachable
+	h = NewHistory[int](0)
+	h.Do(1)
+	h.Checkpoint("one")
+	h.Undo()
+	h.Do(2)
+	_, ok = h.UndoTo("one")
+	assert.False(t, ok)
+}
+
+func TestHistoryClear(t *testing.T) {
+	h := NewHistory[int](0)
+	h.Do(1)
+	h.Checkpoint("cp")
+	h.Do(2)
+	h.Undo()
+	h.Clear()
+
+	assert.True(t, h.IsEmpty())
+	assert.False(t, h.CanUndo())
+	assert.False(t, h.CanRedo())
+	_, ok := h.UndoTo("cp")
+	assert.False(t, ok)
+}
+
+func TestHistoryCheckInvariantsDetectsCorruption(t *testing.T) {
+	h := NewHistory[string](0)
+	h.Do("a")
+	h.Do("b")
+	assert.NoError(t, h.CheckInvariants())
+
+	h.groupDepth = 1
+	assert.Error(t, h.CheckInvariants())
+	h.groupDepth = 0
+
+	h.undo.Peek().seq = 0
+	assert.Error(t, h.CheckInvariants())
+}
+
+func FuzzHistory(f *testing.F) {
+	f.Add(uint8(0), []byte{0, 8, 1, 2, 3, 16, 4, 2})
+	f.Add(uint8(2), []byte{0, 8, 16, 24, 1, 1, 2})
+	f.Fuzz(func(t *testing.T, depth uint8, ops []byte) {
+		maxDepth := int(depth % 4)
+		h := NewHistory[int](maxDepth)
+		var undo, redo [][]int // steps, oldest first and most recently undone last
+		var group []int
+		groupDepth := 0
+		for i, op := range ops {
+			v := int(op >> 3)
+			switch op % 6 {
+			case 0, 1:
+				h.Do(v)
+				if groupDepth > 0 {
+					group = append(group, v)
+				} else {
+					undo, redo = append(undo, []int{v}), nil
+				}
+			case 2:
+				got, ok := h.Undo()
+				if ok != (groupDepth == 0 && len(undo) > 0) {
+					t.Fatalf("op %d: Undo ok = %t", i, ok)
+				}
+				if ok {
+					step := undo[len(undo)-1]
+					undo, redo = undo[:len(undo)-1], append(redo, step)
+					assert.Equal(t, reversed(step), got, "op %d: Undo", i)
+				}
+			case 3:
+				got, ok := h.Redo()
+				if ok != (groupDepth == 0 && len(redo) > 0) {
+					t.Fatalf("op %d: Redo ok = %t", i, ok)
+				}
+				if ok {
+					step := redo[len(redo)-1]
+					redo, undo = redo[:len(redo)-1], append(undo, step)
+					assert.Equal(t, step, got, "op %d: Redo", i)
+				}
+			case 4:
+				h.Begin()
+				groupDepth++
+			case 5:
+				if v%2 == 0 {
+					got := h.Rollback()
+					if groupDepth > 0 {
+						assert.Equal(t, reversed(group), got, "op %d: Rollback", i)
+					} else {
+						assert.Nil(t, got, "op %d: Rollback", i)
+					}
+					group, groupDepth = nil, 0
+				} else if groupDepth > 0 {
+					h.Commit()
+					if groupDepth--; groupDepth == 0 {
+						if len(group) > 0 {
+							undo, redo = append(undo, group), nil
+						}
+						group = nil
+					}
+				}
+			}
+			if maxDepth > 0 && len(undo) > maxDepth {
+				undo = undo[len(undo)-maxDepth:]
+			}
+
+			if err := h.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(undo)+len(redo), h.Size(), "after op %d (%d)", i, op)
+			assert.Equal(t, groupDepth == 0 && len(undo) > 0, h.CanUndo(), "after op %d (%d)", i, op)
+			assert.Equal(t, groupDepth == 0 && len(redo) > 0, h.CanRedo(), "after op %d (%d)", i, op)
+		}
+	})
+}
+
+func benchHistoryDoUndo[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		h := NewHistory[T](0)
+		for _, v := range values {
+			h.Do(v)
+		}
+		for h.CanUndo() {
+			h.Undo()
+		}
+	}
+}
+
+func BenchmarkHistoryDoUndo(b *testing.B) {
+	runBench(b, benchHistoryDoUndo[int], benchHistoryDoUndo[string])
+}
@@ -0,0 +1,367 @@
+package gocontainers
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// HyperLogLog estimates the number of distinct values added to it using a
+// fixed amount of memory: 2^precision one-byte registers, for a typical
+// relative error of 1.04/sqrt(2^precision), about 0.8% at precision 14.
+//
+// While few values have been added it uses a sparse representation that
+// stores only the registers in use, at a higher internal precision, so
+// small counts are both cheap and nearly exact. Each register in use takes
+// four bytes, and it switches to the dense registers once that would be
+// more memory than they take.
+//
+// HyperLogLogs that are merged or exchanged with MarshalBinary must use
+// the same Hasher; see Hasher.
+type HyperLogLog[T comparable] struct {
+	p         uint8
+	registers []uint8  // dense registers, nil while sparse
+	sparse    []uint32 // sparse registers packed by hllPack, sorted by index
+	buffer    []uint32 // sparse registers not yet merged into sparse
+	hash      Hasher[T]
+	id        uint64 // hasherID of hash
+}
+
+const (
+	hllMinPrecision = 4
+	hllMaxPrecision = 18
+	// hllSparsePrecision is the precision of the sparse representation.
+	hllSparsePrecision = 25
+
+	hllModeSparse = 0
+	hllModeDense  = 1
+	// hllHeader is the encoded size of the precision, the mode and the
+	// hasher fingerprint.
+	hllHeader = 10
+)
+
+// NewHyperLogLog returns an empty HyperLogLog with 2^precision registers,
+// hashed with hash/maphash and the process-wide default seed. precision
+// must be between 4 and 18.
+func NewHyperLogLog[T comparable](precision int) *HyperLogLog[T] {
+	return NewHyperLogLogWithHasher(precision, defaultHasher[T]())
+}
+
+// NewHyperLogLogWithHasher is like NewHyperLogLog but uses hash.
+func NewHyperLogLogWithHasher[T comparable](precision int, hash Hasher[T]) *HyperLogLog[T] {
+	if precision < hllMinPrecision || precision > hllMaxPrecision {
+		panic("HyperLogLog precision must be between 4 and 18")
+	}
+	return &HyperLogLog[T]{p: uint8(precision), hash: hash, id: hasherID(hash)}
+}
+
+// NewHyperLogLogFromSet returns a HyperLogLog with 2^precision registers
+// holding the elements of s.
+func NewHyperLogLogFromSet[T comparable](s *Set[T], precision int) *HyperLogLog[T] {
+	return NewHyperLogLogFromSetWithHasher(s, precision, defaultHasher[T]())
+}
+
+// NewHyperLogLogFromSetWithHasher is like NewHyperLogLogFromSet but uses
+// hash.
+func NewHyperLogLogFromSetWithHasher[T comparable](s *Set[T], precision int, hash Hasher[T]) *HyperLogLog[T] {
+	h := NewHyperLogLogWithHasher(precision, hash)
+	h.AddSet(s)
+	return h
+}
+
+// hllRank splits h into a register index of p bits and the rank of the
+// remaining bits: the position of their leftmost 1, counting from 1.
+func hllRank(h uint64, p uint8) (index uint32, rank uint8) {
+	index = uint32(h >> (64 - p))
+	rank = uint8(min(bits.LeadingZeros64(h<<p)+1, 64-int(p)+1))
+	return index, rank
+}
+
+// hllPack packs a sparse register index, which fits in hllSparsePrecision
+// bits, and its rank, which fits in six, so that packed registers sort by
+// index.
+func hllPack(index uint32, rank uint8) uint32 {
+	return index<<6 | uint32(rank)
+}
+
+func hllUnpack(e uint32) (index uint32, rank uint8) {
+	return e >> 6, uint8(e & 63)
+}
+
+// mergeSparse merges the sorted packed registers a with the unsorted b into
+// a new sorted slice, keeping the highest rank for each index.
+func mergeSparse(a, b []uint32) []uint32 {
+	b = slices.Clone(b)
+	slices.Sort(b)
+	merged := make([]uint32, 0, len(a)+len(b))
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		var e uint32
+		if j == len(b) || i < len(a) && a[i] < b[j] {
+			e, i = a[i], i+1
+		} else {
+			e, j = b[j], j+1
+		}
+		if n := len(merged); n > 0 && merged[n-1]>>6 == e>>6 {
+			merged[n-1] = max(merged[n-1], e)
+		} else {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// denseRank converts a sparse register to the dense register it falls in
+// and the rank it contributes there.
+func (h *HyperLogLog[T]) denseRank(index uint32, rank uint8) (uint32, uint8) {
+	extra := hllSparsePrecision - h.p
+	rest := index & (1<<extra - 1)
+	if rest != 0 {
+		return index >> extra, uint8(bits.LeadingZeros32(rest) - (32 - int(extra)) + 1)
+	}
+	return index >> extra, extra + rank
+}
+
+// Precision returns the base-2 logarithm of the number of registers.
+func (h *HyperLogLog[T]) Precision() int {
+	return int(h.p)
+}
+
+// Add adds v to the estimator.
+func (h *HyperLogLog[T]) Add(v T) {
+	x := h.hash(v)
+	if h.registers != nil {
+		index, rank := hllRank(x, h.p)
+		h.registers[index] = max(h.registers[index], rank)
+		return
+	}
+	index, rank := hllRank(x, hllSparsePrecision)
+	i, found := slices.BinarySearchFunc(h.sparse, index, func(e, index uint32) int {
+		return cmp.Compare(e>>6, index)
+	})
+	if found {
+		if _, old := hllUnpack(h.sparse[i]); old >= rank {
+			return
+		}
+	}
+	// new registers collect in the buffer so that each Add need not move
+	// the sorted ones; merging it once it holds 1/16 of the register count
+	// keeps that cost amortized
+	h.buffer = append(h.buffer, hllPack(index, rank))
+	if len(h.buffer) >= max(1, (1<<h.p)/16) || 4*(len(h.sparse)+len(h.buffer)) > 1<<h.p {
+		h.flush()
+	}
+}
+
+// AddSet adds every element of s.
+func (h *HyperLogLog[T]) AddSet(s *Set[T]) {
+	for element := range s.elements {
+		h.Add(element)
+	}
+}
+
+// flush merges the buffer into the sorted sparse registers, and switches to
+// dense registers once the sparse ones, at four bytes each, would take more
+// than the 2^p bytes of the dense ones.
+func (h *HyperLogLog[T]) flush() {
+	if len(h.buffer) == 0 {
+		return
+	}
+	h.sparse = mergeSparse(h.sparse, h.buffer)
+	h.buffer = h.buffer[:0]
+	if 4*len(h.sparse) > 1<<h.p {
+		h.densify()
+	}
+}
+
+func (h *HyperLogLog[T]) densify() {
+	if h.registers != nil {
+		return
+	}
+	h.registers = make([]uint8, 1<<h.p)
+	h.addSparse(h.sparse)
+	h.addSparse(h.buffer)
+	h.sparse, h.buffer = nil, nil
+}
+
+// addSparse adds packed sparse registers to the dense registers.
+func (h *HyperLogLog[T]) addSparse(sparse []uint32) {
+	for _, e := range sparse {
+		i, r := h.denseRank(hllUnpack(e))
+		h.registers[i] = max(h.registers[i], r)
+	}
+}
+
+// Estimate returns the estimated number of distinct values added.
+func (h *HyperLogLog[T]) Estimate() uint64 {
+	h.flush()
+	if h.registers == nil {
+		return uint64(math.Round(linearCounting(1<<hllSparsePrecision, 1<<hllSparsePrecision-len(h.sparse))))
+	}
+
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = linearCounting(len(h.registers), zeros)
+	}
+	return uint64(math.Round(estimate))
+}
+
+// linearCounting estimates the number of distinct values hashed into m
+// buckets of which empty are still empty.
+func linearCounting(m, empty int) float64 {
+	return float64(m) * math.Log(float64(m)/float64(empty))
+}
+
+// hllAlpha is the bias correction constant for m registers.
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+func (h *HyperLogLog[T]) IsEmpty() bool {
+	if h.registers == nil {
+		return len(h.sparse) == 0 && len(h.buffer) == 0
+	}
+	for _, r := range h.registers {
+		if r != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear removes every value and returns to the sparse representation.
+func (h *HyperLogLog[T]) Clear() {
+	h.registers, h.sparse, h.buffer = nil, nil, nil
+}
+
+// Merge adds the values of other to h, so that h estimates the number of
+// distinct values added to either. It returns ErrIncompatible if the
+// precisions or hashers differ.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.p != other.p {
+		return fmt.Errorf("HyperLogLog: %w: precision %d vs %d", ErrIncompatible, h.p, other.p)
+	}
+	if h.id != other.id {
+		return fmt.Errorf("HyperLogLog: %w: different hashers", ErrIncompatible)
+	}
+	if h.registers == nil && other.registers == nil {
+		h.buffer = append(append(h.buffer, other.sparse...), other.buffer...)
+		h.flush()
+		return nil
+	}
+
+	h.densify()
+	if other.registers != nil {
+		for i, r := range other.registers {
+			h.registers[i] = max(h.registers[i], r)
+		}
+		return nil
+	}
+	h.addSparse(other.sparse)
+	h.addSparse(other.buffer)
+	return nil
+}
+
+// MarshalBinary encodes the precision, a fingerprint of the hasher and the
+// registers. Sparse estimators encode only the registers in use.
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	if h.registers != nil {
+		data := make([]byte, 0, hllHeader+len(h.registers))
+		data = append(data, h.p, hllModeDense)
+		data = binary.LittleEndian.AppendUint64(data, h.id)
+		return append(data, h.registers...), nil
+	}
+
+	sparse := mergeSparse(h.sparse, h.buffer)
+	data := make([]byte, 0, hllHeader+4+5*len(sparse))
+	data = append(data, h.p, hllModeSparse)
+	data = binary.LittleEndian.AppendUint64(data, h.id)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(sparse)))
+	for _, e := range sparse {
+		index, rank := hllUnpack(e)
+		data = binary.LittleEndian.AppendUint32(data, index)
+		data = append(data, rank)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the precision and contents of the estimator with
+// data produced by MarshalBinary. The estimator keeps its own hasher, and it
+// returns ErrIncompatible if the data was built with a different one.
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("HyperLogLog: %w: %s", ErrInvalidEncoding, fmt.Sprintf(format, args...))
+	}
+	if h.hash == nil {
+		return fmt.Errorf("HyperLogLog: %w", ErrNoHasher)
+	}
+	if len(data) < hllHeader {
+		return invalid("truncated header")
+	}
+	p := data[0]
+	if p < hllMinPrecision || p > hllMaxPrecision {
+		return invalid("precision %d", p)
+	}
+	if binary.LittleEndian.Uint64(data[2:]) != h.id {
+		return fmt.Errorf("HyperLogLog: %w: data was built with a different hasher", ErrIncompatible)
+	}
+
+	switch data[1] {
+	case hllModeDense:
+		if len(data) != hllHeader+1<<p {
+			return invalid("%d registers for precision %d", len(data)-hllHeader, p)
+		}
+		for _, r := range data[hllHeader:] {
+			if int(r) > 64-int(p)+1 {
+				return invalid("register value %d", r)
+			}
+		}
+		h.p, h.registers, h.sparse, h.buffer = p, slices.Clone(data[hllHeader:]), nil, nil
+	case hllModeSparse:
+		if len(data) < hllHeader+4 {
+			return invalid("truncated sparse header")
+		}
+		n := int(binary.LittleEndian.Uint32(data[hllHeader:]))
+		if n > (1<<p)/4 || len(data) != hllHeader+4+5*n {
+			return invalid("%d sparse registers in %d bytes", n, len(data))
+		}
+		sparse := make([]uint32, n)
+		for i, pos := 0, hllHeader+4; i < n; i, pos = i+1, pos+5 {
+			index := binary.LittleEndian.Uint32(data[pos:])
+			rank := data[pos+4]
+			if index >= 1<<hllSparsePrecision || rank == 0 || rank > 64-hllSparsePrecision+1 {
+				return invalid("sparse register %d with rank %d", index, rank)
+			}
+			sparse[i] = hllPack(index, rank)
+		}
+		slices.Sort(sparse)
+		for i := 1; i < n; i++ {
+			if sparse[i]>>6 == sparse[i-1]>>6 {
+				return invalid("duplicate sparse register %d", sparse[i]>>6)
+			}
+		}
+		h.p, h.registers, h.sparse, h.buffer = p, nil, sparse, nil
+	default:
+		return invalid("unknown mode %d", data[1])
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package gocontainers
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hllStdError is the standard relative error of a HyperLogLog with the
+// given precision.
+func hllStdError(precision int) float64 {
+	return 1.04 / math.Sqrt(float64(int(1)<<precision))
+}
+
+func newTestHLL(precision, from, to int) *HyperLogLog[int] {
+	h := NewHyperLogLogWithHasher(precision, mixHasher)
+	for i := from; i < to; i++ {
+		h.Add(i)
+	}
+	return h
+}
+
+func relativeError(estimate uint64, n int) float64 {
+	return math.Abs(float64(estimate)-float64(n)) / float64(n)
+}
+
+func TestHyperLogLogPrecision(t *testing.T) {
+	assert.Equal(t, 14, NewHyperLogLog[int](14).Precision())
+	assert.Panics(t, func() { NewHyperLogLog[int](3) })
+	assert.Panics(t, func() { NewHyperLogLog[int](19) })
+}
+
+func TestHyperLogLogEmpty(t *testing.T) {
+	h := NewHyperLogLog[string](10)
+	assert.True(t, h.IsEmpty())
+	assert.Equal(t, uint64(0), h.Estimate())
+
+	h.Add("a")
+	h.Add("a")
+	assert.False(t, h.IsEmpty())
+	assert.Equal(t, uint64(1), h.Estimate())
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	assert.Equal(t, uint64(0), h.Estimate())
+}
+
+func TestHyperLogLogSparse(t *testing.T) {
+	h := newTestHLL(14, 0, 4000)
+	require.Nil(t, h.registers, "still sparse")
+	// at the sparse precision, small counts are close to exact
+	assert.InDelta(t, 4000, h.Estimate(), 2)
+
+	for i := 4000; i < 5000; i++ {
+		h.Add(i)
+	}
+	require.NotNil(t, h.registers, "dense past a quarter of the registers")
+	assert.Nil(t, h.sparse)
+	assert.Less(t, relativeError(h.Estimate(), 5000), 4*hllStdError(14))
+}
+
+func TestHyperLogLogSparseMemory(t *testing.T) {
+	// the sparse form never takes much more memory than the dense registers
+	// it replaces, up to the point where it switches
+	h := NewHyperLogLogWithHasher(14, mixHasher)
+	peak := 0
+	for i := 0; h.registers == nil; i++ {
+		peak = max(peak, 4*(cap(h.sparse)+cap(h.buffer)))
+		h.Add(i)
+	}
+	assert.LessOrEqual(t, peak, 3<<14/2, "peak %d bytes", peak)
+	assert.InDelta(t, 1<<14/4, h.Estimate(), 1<<14/40)
+}
+
+func TestHyperLogLogDensifyMatchesDense(t *testing.T) {
+	// values added while sparse must land in the same dense registers as
+	// values added after the switch
+	sparse := newTestHLL(10, 0, 200)
+	require.Nil(t, sparse.registers)
+	sparse.densify()
+
+	dense := NewHyperLogLogWithHasher(10, mixHasher)
+	dense.densify()
+	for i := range 200 {
+		dense.Add(i)
+	}
+	assert.Equal(t, dense.registers, sparse.registers)
+}
+
+func TestHyperLogLogErrorBounds(t *testing.T) {
+	for _, precision := range []int{10, 14} {
+		for _, n := range []int{100, 1000, 10000, 100000, 1000000} {
+			h := newTestHLL(precision, 0, n)
+			assert.Less(t, relativeError(h.Estimate(), n), 4*hllStdError(precision),
+				"precision %d, n %d: estimate %d", precision, n, h.Estimate())
+		}
+	}
+}
+
+func TestHyperLogLogErrorDistribution(t *testing.T) {
+	// independent runs over disjoint values should show the textbook spread:
+	// little bias and a root mean square error near the standard error
+	const precision, n, runs = 10, 20000, 60
+	sumErr, sumSq := 0.0, 0.0
+	for run := range runs {
+		h := newTestHLL(precision, run*n, (run+1)*n)
+		e := (float64(h.Estimate()) - n) / n
+		sumErr += e
+		sumSq += e * e
+	}
+	bias := sumErr / runs
+	rms := math.Sqrt(sumSq / runs)
+	assert.Less(t, math.Abs(bias), hllStdError(precision), "bias %.4f", bias)
+	assert.Less(t, rms, 1.5*hllStdError(precision), "rms error %.4f", rms)
+	assert.Greater(t, rms, 0.5*hllStdError(precision), "rms error %.4f", rms)
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int // of the second estimator; the first holds [0, 60000)
+	}{
+		{"dense into dense", 30000, 90000},
+		{"sparse into dense", 59900, 60100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestHLL(14, 0, 60000)
+			b := newTestHLL(14, tt.from, tt.to)
+			require.NoError(t, a.Merge(b))
+			union := max(60000, tt.to)
+			assert.Less(t, relativeError(a.Estimate(), union), 4*hllStdError(14))
+		})
+	}
+
+	t.Run("dense into sparse", func(t *testing.T) {
+		a := newTestHLL(14, 0, 100)
+		require.NoError(t, a.Merge(newTestHLL(14, 0, 60000)))
+		assert.Equal(t, newTestHLL(14, 0, 60000).registers, a.registers)
+	})
+
+	t.Run("sparse into sparse", func(t *testing.T) {
+		a := newTestHLL(14, 0, 1000)
+		require.NoError(t, a.Merge(newTestHLL(14, 500, 1500)))
+		assert.Nil(t, a.registers)
+		assert.InDelta(t, 1500, a.Estimate(), 2)
+
+		// crossing the threshold while merging switches to dense
+		require.NoError(t, a.Merge(newTestHLL(14, 1500, 4500)))
+		assert.NotNil(t, a.registers)
+	})
+
+	err := newTestHLL(14, 0, 10).Merge(newTestHLL(12, 0, 10))
+	assert.True(t, errors.Is(err, ErrIncompatible))
+
+	t.Run("default hasher", func(t *testing.T) {
+		a, b := NewHyperLogLog[int](14), NewHyperLogLog[int](14)
+		for i := range 50000 {
+			a.Add(i)
+			b.Add(i)
+		}
+		require.NoError(t, a.Merge(b))
+		assert.Less(t, relativeError(a.Estimate(), 50000), 4*hllStdError(14))
+
+		c := NewHyperLogLogWithHasher(14, NewMaphashHasher[int]())
+		c.Add(1)
+		assert.True(t, errors.Is(a.Merge(c), ErrIncompatible))
+		data, err := a.MarshalBinary()
+		require.NoError(t, err)
+		assert.True(t, errors.Is(c.UnmarshalBinary(data), ErrIncompatible))
+		require.NoError(t, b.UnmarshalBinary(data))
+		assert.Equal(t, a.Estimate(), b.Estimate())
+	})
+}
+
+func TestHyperLogLogFromSet(t *testing.T) {
+	s := NewSet[string]()
+	for _, w := range []string{"a", "b", "c", "a"} {
+		s.Add(w)
+	}
+	h := NewHyperLogLogFromSet(s, 12)
+	assert.Equal(t, 12, h.Precision())
+	assert.Equal(t, uint64(3), h.Estimate())
+
+	// estimators built from the same set hash alike, so merging them
+	// changes nothing
+	require.NoError(t, h.Merge(NewHyperLogLogFromSet(s, 12)))
+	assert.Equal(t, uint64(3), h.Estimate())
+	hw := NewHyperLogLogFromSetWithHasher(s, 12, NewMaphashHasher[string]())
+	assert.Equal(t, uint64(3), hw.Estimate())
+	assert.True(t, errors.Is(h.Merge(hw), ErrIncompatible))
+
+	big := NewSet[int]()
+	for i := range 50000 {
+		big.Add(i)
+	}
+	hb := NewHyperLogLogWithHasher(14, mixHasher)
+	hb.AddSet(big)
+	assert.Equal(t, newTestHLL(14, 0, 50000).registers, hb.registers)
+}
+
+func TestHyperLogLogBinary(t *testing.T) {
+	for _, n := range []int{0, 500, 50000} {
+		h := newTestHLL(12, 0, n)
+		data, err := h.MarshalBinary()
+		require.NoError(t, err)
+
+		g := NewHyperLogLogWithHasher(4, mixHasher)
+		require.NoError(t, g.UnmarshalBinary(data))
+		assert.Equal(t, 12, g.Precision())
+		assert.Equal(t, h.Estimate(), g.Estimate(), "n %d", n)
+		assert.Equal(t, h.registers, g.registers)
+		if h.registers == nil {
+			assert.Equal(t, mergeSparse(h.sparse, h.buffer), g.sparse)
+		}
+
+		// the decoded estimator keeps working
+		g.Add(-1)
+		h.Add(-1)
+		assert.Equal(t, h.Estimate(), g.Estimate())
+	}
+
+	sparse, err := newTestHLL(12, 0, 10).MarshalBinary()
+	require.NoError(t, err)
+	badRank := append([]byte(nil), sparse...)
+	badRank[hllHeader+8] = 0
+	entry := sparse[hllHeader+4 : hllHeader+9]
+	duplicate := append(append(append([]byte(nil), sparse[:hllHeader]...), 2, 0, 0, 0), entry...)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"bad precision", append([]byte{30, hllModeDense}, sparse[2:hllHeader]...)},
+		{"unknown mode", append([]byte{12, 7}, sparse[2:hllHeader]...)},
+		{"zero rank", badRank},
+		{"duplicate register", append(duplicate, entry...)},
+	}
+	h := NewHyperLogLogWithHasher(12, mixHasher)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.UnmarshalBinary(tt.data)
+			assert.True(t, errors.Is(err, ErrInvalidEncoding), "got %v", err)
+		})
+	}
+}
+
+// FuzzHyperLogLogUnmarshal checks that UnmarshalBinary rejects malformed
+// input with ErrInvalidEncoding, or ErrIncompatible for another hasher, and
+// that accepted input decodes to an estimator whose own encoding survives a
+// round trip. Sparse registers may arrive in any order but are always
+// encoded sorted, so the input itself need not be reproduced.
+func FuzzHyperLogLogUnmarshal(f *testing.F) {
+	for _, n := range []int{0, 10, 5000} {
+		data, _ := newTestHLL(8, 0, n).MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h := NewHyperLogLogWithHasher(4, mixHasher)
+		if err := h.UnmarshalBinary(data); err != nil {
+			if !errors.Is(err, ErrInvalidEncoding) && !errors.Is(err, ErrIncompatible) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+		encoded, err := h.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := NewHyperLogLogWithHasher(4, mixHasher)
+		if err := g.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("re-encoded data rejected: %v", err)
+		}
+		again, _ := g.MarshalBinary()
+		if !bytes.Equal(again, encoded) || g.Estimate() != h.Estimate() {
+			t.Fatal("round trip changed the estimator")
+		}
+	})
+}
+
+func benchHyperLogLogAdd[T comparable](b *testing.B, values []T) {
+	for b.Loop() {
+		h := NewHyperLogLog[T](14)
+		for _, v := range values {
+			h.Add(v)
+		}
+	}
+}
+
+func benchHyperLogLogEstimate[T comparable](b *testing.B, values []T) {
+	h := NewHyperLogLog[T](14)
+	for _, v := range values {
+		h.Add(v)
+	}
+	for b.Loop() {
+		h.Estimate()
+	}
+}
+
+func BenchmarkHyperLogLogAdd(b *testing.B) {
+	runBench(b, benchHyperLogLogAdd[int], benchHyperLogLogAdd[string])
+}
+
+func BenchmarkHyperLogLogEstimate(b *testing.B) {
+	runBench(b, benchHyperLogLogEstimate[int], benchHyperLogLogEstimate[string])
+}
@@ -0,0 +1,228 @@
+package gocontainers
+
+import "fmt"
+
+// ListHook holds the links that put a value of type T on an IntrusiveList.
+// Embed a ListHook in your struct for every list the struct should be able
+// to sit on at the same time:
+//
+//	type Conn struct {
+//		idle ListHook[Conn]
+//		all  ListHook[Conn]
+//	}
+//
+// A hook can be on at most one list at a time. The zero value is an
+// unlinked hook.
+type ListHook[T any] struct {
+	owner *T
+	list  *IntrusiveList[T]
+	prev  *ListHook[T]
+	next  *ListHook[T]
+}
+
+// Linked reports whether the hook is currently on a list.
+func (h *ListHook[T]) Linked() bool {
+	return h.list != nil
+}
+
+// IntrusiveList is a doubly linked list of values that carry their own links
+// in an embedded ListHook, so adding a value to the list allocates nothing.
+// The list stores pointers to the values; it never copies them.
+type IntrusiveList[T any] struct {
+	head *ListHook[T]
+	tail *ListHook[T]
+	size int
+	hook func(*T) *ListHook[T]
+}
+
+// NewIntrusiveList creates a new IntrusiveList that links values through
+// the hook returned by hook, e.g. func(c *Conn) *ListHook[Conn] { return &c.idle }.
+func NewIntrusiveList[T any](hook func(*T) *ListHook[T]) *IntrusiveList[T] {
+	return &IntrusiveList[T]{hook: hook}
+}
+
+func (l *IntrusiveList[T]) link(v *T) *ListHook[T] {
+	h := l.hook(v)
+	if h.list != nil {
+		panic("value is already on a list through this hook")
+	}
+	h.owner = v
+	h.list = l
+	l.size++
+	return h
+}
+
+func (l *IntrusiveList[T]) AddFront(v *T) {
+	h := l.link(v)
+	if l.head == nil {
+		l.head = h
+		l.tail = h
+	} else {
+		h.next = l.head
+		l.head.prev = h
+		l.head = h
+	}
+}
+
+func (l *IntrusiveList[T]) AddBack(v *T) {
+	h := l.link(v)
+	if l.tail == nil {
+		l.head = h
+		l.tail = h
+	} else {
+		h.prev = l.tail
+		l.tail.next = h
+		l.tail = h
+	}
+}
+
+func (l *IntrusiveList[T]) RemoveFront() {
+	if l.head != nil {
+		l.unlink(l.head)
+	}
+}
+
+func (l *IntrusiveList[T]) RemoveBack() {
+	if l.tail != nil {
+		l.unlink(l.tail)
+	}
+}
+
+// DeleteNode removes v from the list. It does nothing if v is not on this list.
+func (l *IntrusiveList[T]) DeleteNode(v *T) {
+	h := l.hook(v)
+	if h.list != l {
+		return
+	}
+	l.unlink(h)
+}
+
+func (l *IntrusiveList[T]) unlink(h *ListHook[T]) {
+	if h.prev != nil {
+		h.prev.next = h.next
+	} else {
+		l.head = h.next
+	}
+	if h.next != nil {
+		h.next.prev = h.prev
+	} else {
+		l.tail = h.prev
+	}
+	h.prev = nil
+	h.next = nil
+	h.list = nil
+	h.owner = nil
+	l.size--
+}
+
+// Contains reports whether v is on this list.
+func (l *IntrusiveList[T]) Contains(v *T) bool {
+	return l.hook(v).list == l
+}
+
+func (l *IntrusiveList[T]) Size() int {
+	return l.size
+}
+
+func (l *IntrusiveList[T]) IsEmpty() bool {
+	return l.head == nil
+}
+
+// GetFront returns the value at the front of the list, or nil if it is empty.
+func (l *IntrusiveList[T]) GetFront() *T {
+	if l.head == nil {
+		return nil
+	}
+	return l.head.owner
+}
+
+// GetBack returns the value at the back of the list, or nil if it is empty.
+func (l *IntrusiveList[T]) GetBack() *T {
+	if l.tail == nil {
+		return nil
+	}
+	return l.tail.owner
+}
+
+// Next returns the value after v on this list, or nil if v is at the back
+// or not on this list.
+func (l *IntrusiveList[T]) Next(v *T) *T {
+	h := l.hook(v)
+	if h.list != l || h.next == nil {
+		return nil
+	}
+	return h.next.owner
+}
+
+// Prev returns the value before v on this list, or nil if v is at the front
+// or not on this list.
+func (l *IntrusiveList[T]) Prev(v *T) *T {
+	h := l.hook(v)
+	if h.list != l || h.prev == nil {
+		return nil
+	}
+	return h.prev.owner
+}
+
+// Clear removes all values from the list, unlinking each hook so the values
+// can be added to a list again.
+func (l *IntrusiveList[T]) Clear() {
+	for l.head != nil {
+		l.unlink(l.head)
+	}
+}
+
+// CheckInvariants verifies that the ends are terminated, every next link is
+// mirrored by a prev link, every hook belongs to this list, and the
+// recorded size matches the number of linked values. It is meant for tests.
+func (l *IntrusiveList[T]) CheckInvariants() error {
+	if (l.head == nil) != (l.tail == nil) {
+		return fmt.Errorf("IntrusiveList: only one of head and tail is set")
+	}
+	count := 0
+	var prev *ListHook[T]
+	for h := l.head; h != nil; h = h.next {
+		if h.prev != prev {
+			return fmt.Errorf("IntrusiveList: value %d prev link is not mirrored", count)
+		}
+		if h.list != l || h.owner == nil || l.hook(h.owner) != h {
+			return fmt.Errorf("IntrusiveList: value %d hook does not belong to this list", count)
+		}
+		prev = h
+		count++
+		if count > l.size {
+			return fmt.Errorf("IntrusiveList: more than size %d values linked", l.size)
+		}
+	}
+	if prev != l.tail {
+		return fmt.Errorf("IntrusiveList: last linked value is not the tail")
+	}
+	if count != l.size {
+		return fmt.Errorf("IntrusiveList: size is %d but %d values are linked", l.size, count)
+	}
+	return nil
+}
+
+type IntrusiveIterator[T any] struct {
+	current *ListHook[T]
+}
+
+// Iterator returns an iterator over the list from front to back. The value
+// most recently returned by Next may be deleted without disturbing iteration.
+func (l *IntrusiveList[T]) Iterator() *IntrusiveIterator[T] {
+	return &IntrusiveIterator[T]{current: l.head}
+}
+
+func (it *IntrusiveIterator[T]) HasNext() bool {
+	return it.current != nil
+}
+
+func (it *IntrusiveIterator[T]) Next() *T {
+	if it.current == nil {
+		panic("Iterator is at the end")
+	}
+
+	v := it.current.owner
+	it.current = it.current.next
+	return v
+}
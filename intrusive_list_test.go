@@ -0,0 +1,291 @@
+package gocontainers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConn struct {
+	id   int
+	idle ListHook[testConn]
+	all  ListHook[testConn]
+}
+
+func idleHook(c *testConn) *ListHook[testConn] { return &c.idle }
+func allHook(c *testConn) *ListHook[testConn]  { return &c.all }
+
+func connIDs(l *IntrusiveList[testConn]) []int {
+	ids := []int{}
+	for it := l.Iterator(); it.HasNext(); {
+		ids = append(ids, it.Next().id)
+	}
+	return ids
+}
+
+func TestIntrusiveListAddFrontBack(t *testing.T) {
+	l := NewIntrusiveList(idleHook)
+	c1, c2, c3 := &testConn{id: 1}, &testConn{id: 2}, &testConn{id: 3}
+
+	l.AddBack(c2)
+	l.AddFront(c1)
+	l.AddBack(c3)
+
+	assert.Equal(t, 3, l.Size())
+	assert.Same(t, c1, l.GetFront())
+	assert.Same(t, c3, l.GetBack())
+	assert.Equal(t, []int{1, 2, 3}, connIDs(l))
+	assert.Same(t, c2, l.Next(c1))
+	assert.Same(t, c2, l.Prev(c3))
+	assert.Nil(t, l.Prev(c1))
+	assert.Nil(t, l.Next(c3))
+}
+
+func TestIntrusiveListEmpty(t *testing.T) {
+	l := NewIntrusiveList(idleHook)
+	assert.True(t, l.IsEmpty())
+	assert.Nil(t, l.GetFront())
+	assert.Nil(t, l.GetBack())
+	l.RemoveFront()
+	l.RemoveBack()
+	assert.Equal(t, 0, l.Size())
+	assert.False(t, l.Iterator().HasNext())
+	assert.Panics(t, func() { l.Iterator().Next() })
+}
+
+func TestIntrusiveListDeleteNode(t *testing.T) {
+	conns := []*testConn{{id: 1}, {id: 2}, {id: 3}, {id: 4}}
+	l := NewIntrusiveList(idleHook)
+	for _, c := range conns {
+		l.AddBack(c)
+	}
+
+	l.DeleteNode(conns[0]) // head
+	assert.Equal(t, []int{2, 3, 4}, connIDs(l))
+	assert.Nil(t, l.Prev(l.GetFront()))
+
+	l.DeleteNode(conns[3]) // tail
+	assert.Equal(t, []int{2, 3}, connIDs(l))
+	assert.Nil(t, l.Next(l.GetBack()))
+
+	l.DeleteNode(conns[1])
+	l.DeleteNode(conns[2])
+	assert.True(t, l.IsEmpty())
+	assert.Nil(t, l.GetBack())
+
+	// deleting a value that is not on the list is a no-op
+	l.AddBack(conns[0])
+	l.DeleteNode(conns[1])
+	assert.Equal(t, []int{1}, connIDs(l))
+}
+
+func TestIntrusiveListRemoveFrontBack(t *testing.T) {
+	l := NewIntrusiveList(idleHook)
+	c1, c2, c3 := &testConn{id: 1}, &testConn{id: 2}, &testConn{id: 3}
+	l.AddBack(c1)
+	l.AddBack(c2)
+	l.AddBack(c3)
+
+	l.RemoveFront()
+	l.RemoveBack()
+	assert.Equal(t, []int{2}, connIDs(l))
+	assert.False(t, c1.idle.Linked())
+	assert.False(t, c3.idle.Linked())
+	assert.True(t, c2.idle.Linked())
+}
+
+func TestIntrusiveListMultipleHooks(t *testing.T) {
+	idle := NewIntrusiveList(idleHook)
+	all := NewIntrusiveList(allHook)
+	c1, c2 := &testConn{id: 1}, &testConn{id: 2}
+
+	all.AddBack(c1)
+	all.AddBack(c2)
+	idle.AddBack(c2)
+
+	assert.Equal(t, []int{1, 2}, connIDs(all))
+	assert.Equal(t, []int{2}, connIDs(idle))
+	assert.True(t, idle.Contains(c2))
+	assert.False(t, idle.Contains(c1))
+
+	idle.DeleteNode(c2)
+	assert.True(t, all.Contains(c2))
+	assert.Equal(t, []int{1, 2}, connIDs(all))
+}
+
+func TestIntrusiveListDoubleAddPanics(t *testing.T) {
+	l1 := NewIntrusiveList(idleHook)
+	l2 := NewIntrusiveList(idleHook)
+	c := &testConn{id: 1}
+	l1.AddBack(c)
+
+	assert.Panics(t, func() { l1.AddFront(c) })
+	assert.Panics(t, func() { l2.AddBack(c) })
+
+	// a different list on the same hook only sees values it owns
+	l2.DeleteNode(c)
+	assert.True(t, l1.Contains(c))
+}
+
+func TestIntrusiveListDeleteWhileIterating(t *testing.T) {
+	l := NewIntrusiveList(idleHook)
+	for i := 1; i <= 5; i++ {
+		l.AddBack(&testConn{id: i})
+	}
+
+	for it := l.Iterator(); it.HasNext(); {
+		c := it.Next()
+		if c.id%2 == 0 {
+			l.DeleteNode(c)
+		}
+	}
+	assert.Equal(t, []int{1, 3, 5}, connIDs(l))
+}
+
+func TestIntrusiveListClear(t *testing.T) {
+	l := NewIntrusiveList(idleHook)
+	c1, c2 := &testConn{id: 1}, &testConn{id: 2}
+	l.AddBack(c1)
+	l.AddBack(c2)
+
+	l.Clear()
+	assert.True(t, l.IsEmpty())
+	assert.Equal(t, 0, l.Size())
+	assert.False(t, c1.idle.Linked())
+
+	// values can be relinked after Clear
+	l.AddBack(c2)
+	assert.Equal(t, []int{2}, connIDs(l))
+}
+
+func TestIntrusiveListNoAllocs(t *testing.T) {
+	l := NewIntrusiveList(idleHook)
+	c := &testConn{id: 1}
+	allocs := testing.AllocsPerRun(100, func() {
+		l.AddBack(c)
+		l.DeleteNode(c)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+// FuzzIntrusiveList applies a random sequence of operations, one per input
+// byte, to two lists sharing a pool of values through different hooks and
+// checks both lists against slice models after every step.
+func FuzzIntrusiveList(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3, 4, 5})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		pool := make([]*testConn, 8)
+		for i := range pool {
+			pool[i] = &testConn{id: i}
+		}
+		lists := []*IntrusiveList[testConn]{NewIntrusiveList(idleHook), NewIntrusiveList(allHook)}
+		models := make([][]int, 2)
+
+		for i, op := range ops {
+			which := int(op>>7) & 1
+			l, model := lists[which], models[which]
+			c := pool[int(op>>3)%len(pool)]
+			switch op % 6 {
+			case 0:
+				if !l.Contains(c) {
+					l.AddFront(c)
+					model = append([]int{c.id}, model...)
+				}
+			case 1:
+				if !l.Contains(c) {
+					l.AddBack(c)
+					model = append(model, c.id)
+				}
+			case 2:
+				l.RemoveFront()
+				if len(model) > 0 {
+					model = model[1:]
+				}
+			case 3:
+				l.RemoveBack()
+				if len(model) > 0 {
+					model = model[:len(model)-1]
+				}
+			case 4:
+				l.DeleteNode(c)
+				model = slices.DeleteFunc(model, func(id int) bool { return id == c.id })
+			case 5:
+				if op&0x40 != 0 {
+					l.Clear()
+					model = nil
+				}
+			}
+			models[which] = model
+
+			for j, l := range lists {
+				if err := l.CheckInvariants(); err != nil {
+					t.Fatalf("list %d after op %d (%d): %v", j, i, op, err)
+				}
+				assert.Equal(t, append([]int{}, models[j]...), connIDs(l))
+			}
+		}
+	})
+}
+
+type benchHooked[T any] struct {
+	value T
+	hook  ListHook[benchHooked[T]]
+}
+
+func newBenchIntrusiveList[T any](values []T) (*IntrusiveList[benchHooked[T]], []*benchHooked[T]) {
+	l := NewIntrusiveList(func(v *benchHooked[T]) *ListHook[benchHooked[T]] { return &v.hook })
+	elements := make([]*benchHooked[T], len(values))
+	for i, v := range values {
+		elements[i] = &benchHooked[T]{value: v}
+	}
+	return l, elements
+}
+
+func benchIntrusiveListAddRemove[T any](b *testing.B, values []T) {
+	l, elements := newBenchIntrusiveList(values)
+	for b.Loop() {
+		for _, e := range elements {
+			l.AddBack(e)
+		}
+		for !l.IsEmpty() {
+			l.RemoveFront()
+		}
+	}
+}
+
+func benchIntrusiveListContains[T any](b *testing.B, values []T) {
+	l, elements := newBenchIntrusiveList(values)
+	for _, e := range elements {
+		l.AddBack(e)
+	}
+	for b.Loop() {
+		for _, e := range elements {
+			l.Contains(e)
+		}
+	}
+}
+
+func benchIntrusiveListIterate[T any](b *testing.B, values []T) {
+	l, elements := newBenchIntrusiveList(values)
+	for _, e := range elements {
+		l.AddBack(e)
+	}
+	for b.Loop() {
+		for it := l.Iterator(); it.HasNext(); {
+			it.Next()
+		}
+	}
+}
+
+func BenchmarkIntrusiveListAddRemove(b *testing.B) {
+	runBench(b, benchIntrusiveListAddRemove[int], benchIntrusiveListAddRemove[string])
+}
+
+func BenchmarkIntrusiveListContains(b *testing.B) {
+	runBench(b, benchIntrusiveListContains[int], benchIntrusiveListContains[string])
+}
+
+func BenchmarkIntrusiveListIterate(b *testing.B) {
+	runBench(b, benchIntrusiveListIterate[int], benchIntrusiveListIterate[string])
+}
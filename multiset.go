@@ -0,0 +1,226 @@
+package gocontainers
+
+import (
+	"fmt"
+	"iter"
+)
+
+// MultiSet is an unordered collection that, unlike Set, remembers how many
+// times each element was added.
+type MultiSet[T comparable] struct {
+	counts map[T]int
+	size   int
+}
+
+// ElementCount pairs an element of a MultiSet with its number of occurrences.
+type ElementCount[T comparable] struct {
+	Element T
+	Count   int
+}
+
+func NewMultiSet[T comparable]() *MultiSet[T] {
+	return &MultiSet[T]{counts: make(map[T]int)}
+}
+
+// NewMultiSetFromSet creates a MultiSet holding each element of s once.
+func NewMultiSetFromSet[T comparable](s *Set[T]) *MultiSet[T] {
+	m := &MultiSet[T]{counts: make(map[T]int, s.Size())}
+	for element := range s.elements {
+		m.Add(element, 1)
+	}
+	return m
+}
+
+// Add adds n occurrences of element. It does nothing if n <= 0.
+func (m *MultiSet[T]) Add(element T, n int) {
+	if n <= 0 {
+		return
+	}
+	m.counts[element] += n
+	m.size += n
+}
+
+// Remove removes up to n occurrences of element and returns how many were
+// removed.
+func (m *MultiSet[T]) Remove(element T, n int) int {
+	count := m.counts[element]
+	if n <= 0 || count == 0 {
+		return 0
+	}
+	if n >= count {
+		delete(m.counts, element)
+		n = count
+	} else {
+		m.counts[element] = count - n
+	}
+	m.size -= n
+	return n
+}
+
+// Count returns the number of occurrences of element.
+func (m *MultiSet[T]) Count(element T) int {
+	return m.counts[element]
+}
+
+func (m *MultiSet[T]) Contains(element T) bool {
+	return m.counts[element] > 0
+}
+
+// Size returns the total number of occurrences of all elements.
+func (m *MultiSet[T]) Size() int {
+	return m.size
+}
+
+// Distinct returns the number of distinct elements.
+func (m *MultiSet[T]) Distinct() int {
+	return len(m.counts)
+}
+
+func (m *MultiSet[T]) IsEmpty() bool {
+	return m.size == 0
+}
+
+func (m *MultiSet[T]) Clear() {
+	m.counts = make(map[T]int)
+	m.size = 0
+}
+
+// All returns an iterator over the distinct elements and their counts, in
+// unspecified order.
+func (m *MultiSet[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for element, count := range m.counts {
+			if !yield(element, count) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice returns every occurrence of every element, so an element added
+// three times appears three times. The order is unspecified.
+func (m *MultiSet[T]) ToSlice() []T {
+	result := make([]T, 0, m.size)
+	for element, count := range m.counts {
+		for i := 0; i < count; i++ {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// ToSet returns a Set of the distinct elements.
+func (m *MultiSet[T]) ToSet() *Set[T] {
+	s := NewSet[T]()
+	for element := range m.counts {
+		s.Add(element)
+	}
+	return s
+}
+
+// MostCommon returns the k elements with the highest counts, most common
+// first. Ties are broken arbitrarily. If k exceeds Distinct, every element
+// is returned.
+func (m *MultiSet[T]) MostCommon(k int) []ElementCount[T] {
+	if k <= 0 {
+		return []ElementCount[T]{}
+	}
+	// keep the k most common in a min-heap so the least common is evicted
+	h := NewHeap(func(a, b ElementCount[T]) bool { return a.Count < b.Count })
+	for element, count := range m.counts {
+		if h.Len() < k {
+			h.PushItem(NewItem(ElementCount[T]{Element: element, Count: count}))
+			continue
+		}
+		if least, _ := h.Peek(); count > least.Get().Count {
+			least.Update(ElementCount[T]{Element: element, Count: count})
+			h.Update(least)
+		}
+	}
+
+	result := make([]ElementCount[T], h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = h.PopItem().Get()
+	}
+	return result
+}
+
+// Union returns a new multiset where each element's count is the larger of
+// its counts in m and other.
+func (m *MultiSet[T]) Union(other *MultiSet[T]) *MultiSet[T] {
+	result := m.clone()
+	for element, count := range other.counts {
+		if extra := count - result.counts[element]; extra > 0 {
+			result.Add(element, extra)
+		}
+	}
+	return result
+}
+
+// Intersection returns a new multiset where each element's count is the
+// smaller of its counts in m and other.
+func (m *MultiSet[T]) Intersection(other *MultiSet[T]) *MultiSet[T] {
+	result := NewMultiSet[T]()
+	for element, count := range m.counts {
+		result.Add(element, min(count, other.counts[element]))
+	}
+	return result
+}
+
+// Sum returns a new multiset where each element's count is the sum of its
+// counts in m and other.
+func (m *MultiSet[T]) Sum(other *MultiSet[T]) *MultiSet[T] {
+	result := m.clone()
+	for element, count := range other.counts {
+		result.Add(element, count)
+	}
+	return result
+}
+
+// Difference returns a new multiset where each element's count is its count
+// in m minus its count in other, dropping elements that reach zero.
+func (m *MultiSet[T]) Difference(other *MultiSet[T]) *MultiSet[T] {
+	result := NewMultiSet[T]()
+	for element, count := range m.counts {
+		result.Add(element, count-other.counts[element])
+	}
+	return result
+}
+
+// Equal reports whether both multisets hold the same elements with the
+// same counts.
+func (m *MultiSet[T]) Equal(other *MultiSet[T]) bool {
+	if m.size != other.size || len(m.counts) != len(other.counts) {
+		return false
+	}
+	for element, count := range m.counts {
+		if other.counts[element] != count {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MultiSet[T]) clone() *MultiSet[T] {
+	result := &MultiSet[T]{counts: make(map[T]int, len(m.counts)), size: m.size}
+	for element, count := range m.counts {
+		result.counts[element] = count
+	}
+	return result
+}
+
+// CheckInvariants verifies that every stored count is positive and that
+// the total matches their sum. It is meant for tests.
+func (m *MultiSet[T]) CheckInvariants() error {
+	total := 0
+	for element, count := range m.counts {
+		if count <= 0 {
+			return fmt.Errorf("MultiSet: element %v has count %d", element, count)
+		}
+		total += count
+	}
+	if total != m.size {
+		return fmt.Errorf("MultiSet: size is %d but counts sum to %d", m.size, total)
+	}
+	return nil
+}
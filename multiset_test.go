@@ -0,0 +1,209 @@
+package gocontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMultiSet(counts map[string]int) *MultiSet[string] {
+	m := NewMultiSet[string]()
+	for element, count := range counts {
+		m.Add(element, count)
+	}
+	return m
+}
+
+func multiSetCounts(m *MultiSet[string]) map[string]int {
+	counts := map[string]int{}
+	for element, count := range m.All() {
+		counts[element] = count
+	}
+	return counts
+}
+
+func TestMultiSetAddRemove(t *testing.T) {
+	m := NewMultiSet[string]()
+	assert.True(t, m.IsEmpty())
+
+	m.Add("a", 3)
+	m.Add("b", 1)
+	m.Add("a", 2)
+	m.Add("c", 0)
+	m.Add("c", -1)
+	assert.Equal(t, 5, m.Count("a"))
+	assert.Equal(t, 0, m.Count("c"))
+	assert.False(t, m.Contains("c"))
+	assert.Equal(t, 6, m.Size())
+	assert.Equal(t, 2, m.Distinct())
+
+	assert.Equal(t, 2, m.Remove("a", 2))
+	assert.Equal(t, 3, m.Count("a"))
+	assert.Equal(t, 1, m.Remove("b", 5))
+	assert.False(t, m.Contains("b"))
+	assert.Equal(t, 0, m.Remove("missing", 1))
+	assert.Equal(t, 0, m.Remove("a", 0))
+	assert.Equal(t, 3, m.Size())
+	assert.Equal(t, 1, m.Distinct())
+
+	m.Clear()
+	assert.True(t, m.IsEmpty())
+	assert.Equal(t, 0, m.Distinct())
+}
+
+func TestMultiSetToSlice(t *testing.T) {
+	m := newMultiSet(map[string]int{"a": 2, "b": 1})
+	assert.ElementsMatch(t, []string{"a", "a", "b"}, m.ToSlice())
+}
+
+func TestMultiSetSetConversion(t *testing.T) {
+	s := NewSet[string]()
+	s.Add("x")
+	s.Add("y")
+
+	m := NewMultiSetFromSet(s)
+	assert.Equal(t, 1, m.Count("x"))
+	assert.Equal(t, 2, m.Size())
+
+	m.Add("x", 4)
+	m.Add("z", 1)
+	assert.ElementsMatch(t, []string{"x", "y", "z"}, m.ToSet().ToSlice())
+}
+
+func TestMultiSetMostCommon(t *testing.T) {
+	m := newMultiSet(map[string]int{"a": 1, "b": 5, "c": 3, "d": 4, "e": 2})
+
+	assert.Equal(t, []ElementCount[string]{{"b", 5}, {"d", 4}, {"c", 3}}, m.MostCommon(3))
+	assert.Equal(t, []ElementCount[string]{{"b", 5}}, m.MostCommon(1))
+	assert.Len(t, m.MostCommon(10), 5)
+	assert.Equal(t, "a", m.MostCommon(10)[4].Element)
+	assert.Empty(t, m.MostCommon(0))
+	assert.Empty(t, NewMultiSet[string]().MostCommon(3))
+}
+
+func TestMultiSetOperations(t *testing.T) {
+	a := newMultiSet(map[string]int{"x": 3, "y": 1})
+	b := newMultiSet(map[string]int{"x": 1, "y": 2, "z": 4})
+
+	tests := []struct {
+		name string
+		got  *MultiSet[string]
+		want map[string]int
+	}{
+		{"Union", a.Union(b), map[string]int{"x": 3, "y": 2, "z": 4}},
+		{"Intersection", a.Intersection(b), map[string]int{"x": 1, "y": 1}},
+		{"Sum", a.Sum(b), map[string]int{"x": 4, "y": 3, "z": 4}},
+		{"Difference", a.Difference(b), map[string]int{"x": 2}},
+		{"ReverseDifference", b.Difference(a), map[string]int{"y": 1, "z": 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, multiSetCounts(tt.got))
+			size := 0
+			for _, count := range tt.want {
+				size += count
+			}
+			assert.Equal(t, size, tt.got.Size())
+		})
+	}
+
+	// operands are unchanged
+	assert.Equal(t, map[string]int{"x": 3, "y": 1}, multiSetCounts(a))
+	assert.Equal(t, map[string]int{"x": 1, "y": 2, "z": 4}, multiSetCounts(b))
+}
+
+func TestMultiSetEqual(t *testing.T) {
+	a := newMultiSet(map[string]int{"x": 2, "y": 1})
+	assert.True(t, a.Equal(newMultiSet(map[string]int{"y": 1, "x": 2})))
+	assert.False(t, a.Equal(newMultiSet(map[string]int{"x": 1, "y": 2})))
+	assert.False(t, a.Equal(newMultiSet(map[string]int{"x": 2, "y": 1, "z": 1})))
+}
+
+func FuzzMultiSet(f *testing.F) {
+	f.Add([]byte{0, 9, 17, 2, 10, 3, 4})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		m := NewMultiSet[int]()
+		model := map[int]int{}
+		for i, op := range ops {
+			v, n := int(op>>5), int(op>>2)%4
+			switch op % 4 {
+			case 0, 1:
+				m.Add(v, n)
+				if n > 0 {
+					model[v] += n
+				}
+			case 2:
+				want := min(n, model[v])
+				if model[v] -= want; model[v] == 0 {
+					delete(model, v)
+				}
+				if got := m.Remove(v, n); got != want {
+					t.Fatalf("op %d: Remove(%d, %d) = %d, want %d", i, v, n, got, want)
+				}
+			case 3:
+				if n == 0 {
+					m.Clear()
+					clear(model)
+				}
+			}
+
+			if err := m.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			size := 0
+			for v := range 8 {
+				assert.Equal(t, model[v], m.Count(v), "after op %d (%d)", i, op)
+				size += model[v]
+			}
+			assert.Equal(t, size, m.Size())
+			assert.Equal(t, len(model), m.Distinct())
+		}
+	})
+}
+
+func benchMultiSetAddRemove[T comparable](b *testing.B, values []T) {
+	for b.Loop() {
+		m := NewMultiSet[T]()
+		for _, v := range values {
+			m.Add(v, 2)
+		}
+		for _, v := range values {
+			m.Remove(v, 2)
+		}
+	}
+}
+
+func benchMultiSetContains[T comparable](b *testing.B, values []T) {
+	m := NewMultiSet[T]()
+	for _, v := range values {
+		m.Add(v, 1)
+	}
+	for b.Loop() {
+		for _, v := range values {
+			m.Contains(v)
+		}
+	}
+}
+
+func benchMultiSetIterate[T comparable](b *testing.B, values []T) {
+	m := NewMultiSet[T]()
+	for _, v := range values {
+		m.Add(v, 1)
+	}
+	for b.Loop() {
+		for range m.All() {
+		}
+	}
+}
+
+func BenchmarkMultiSetAddRemove(b *testing.B) {
+	runBench(b, benchMultiSetAddRemove[int], benchMultiSetAddRemove[string])
+}
+
+func BenchmarkMultiSetContains(b *testing.B) {
+	runBench(b, benchMultiSetContains[int], benchMultiSetContains[string])
+}
+
+func BenchmarkMultiSetIterate(b *testing.B) {
+	runBench(b, benchMultiSetIterate[int], benchMultiSetIterate[string])
+}
@@ -0,0 +1,464 @@
+package gocontainers
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+// PersistentSet is an immutable set backed by a hash array mapped trie
+// (HAMT). With and Without never modify the receiver; they return a new
+// version that shares all untouched parts of the trie with the old one, so
+// keeping old snapshots around is cheap. A PersistentSet is safe for
+// concurrent use.
+//
+// The zero value is an empty set hashed with hash/maphash. Every set built
+// up from a zero value uses the same seed, so they can be compared and
+// diffed efficiently with each other.
+type PersistentSet[T comparable] struct {
+	root *hamtNode[T]
+	size int
+	hash *Hasher[T] // shared by every version derived from the same set; nil for the zero value
+}
+
+// zeroSetSeed hashes the elements of PersistentSets that grew from a zero
+// value.
+var zeroSetSeed = maphash.MakeSeed()
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamtNode is a trie node. Below 64 bits of shift, bitmap records which of
+// the 32 slots are present and entries holds them in slot order. Once the
+// hash is exhausted the node is a collision bucket: bitmap is unused and
+// entries are leaves that all share the same hash.
+type hamtNode[T comparable] struct {
+	bitmap  uint32
+	entries []hamtEntry[T]
+}
+
+// hamtEntry is either a leaf holding value or, if child is set, a subtree.
+type hamtEntry[T comparable] struct {
+	hash  uint64
+	value T
+	child *hamtNode[T]
+}
+
+// NewPersistentSet returns an empty PersistentSet hashed with hash/maphash.
+func NewPersistentSet[T comparable]() *PersistentSet[T] {
+	return NewPersistentSetWithHasher(NewMaphashHasher[T]())
+}
+
+// NewPersistentSetWithHasher returns an empty PersistentSet that uses hash.
+func NewPersistentSetWithHasher[T comparable](hash Hasher[T]) *PersistentSet[T] {
+	return &PersistentSet[T]{root: &hamtNode[T]{}, hash: &hash}
+}
+
+func (s *PersistentSet[T]) derive(root *hamtNode[T], size int) *PersistentSet[T] {
+	return &PersistentSet[T]{root: root, size: size, hash: s.hash}
+}
+
+// hasher returns the set's Hasher, falling back to zeroSetSeed for sets that
+// grew from a zero value.
+func (s *PersistentSet[T]) hasher() Hasher[T] {
+	if s.hash == nil {
+		return func(v T) uint64 { return maphash.Comparable(zeroSetSeed, v) }
+	}
+	return *s.hash
+}
+
+// trie returns the root node, which is nil in the zero value.
+func (s *PersistentSet[T]) trie() *hamtNode[T] {
+	if s.root == nil {
+		return &hamtNode[T]{}
+	}
+	return s.root
+}
+
+// With returns a version of the set that also contains element. If element
+// is already present, s itself is returned.
+func (s *PersistentSet[T]) With(element T) *PersistentSet[T] {
+	root, added := s.trie().insert(hamtEntry[T]{hash: s.hasher()(element), value: element}, 0)
+	if !added {
+		return s
+	}
+	return s.derive(root, s.size+1)
+}
+
+// Without returns a version of the set that does not contain element. If
+// element is not present, s itself is returned.
+func (s *PersistentSet[T]) Without(element T) *PersistentSet[T] {
+	root, removed := s.trie().remove(s.hasher()(element), element, 0)
+	if !removed {
+		return s
+	}
+	return s.derive(root, s.size-1)
+}
+
+func (s *PersistentSet[T]) Contains(element T) bool {
+	return s.trie().contains(s.hasher()(element), element, 0)
+}
+
+func (s *PersistentSet[T]) Size() int {
+	return s.size
+}
+
+func (s *PersistentSet[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// All returns an iterator over the elements in unspecified order.
+func (s *PersistentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.trie().walk(yield)
+	}
+}
+
+func (s *PersistentSet[T]) ToSlice() []T {
+	result := make([]T, 0, s.size)
+	for element := range s.All() {
+		result = append(result, element)
+	}
+	return result
+}
+
+// Union returns a set containing all elements from both sets.
+func (s *PersistentSet[T]) Union(other *PersistentSet[T]) *PersistentSet[T] {
+	result, rest := s, other
+	if other.size > s.size && other.hash == s.hash {
+		result, rest = other, s
+	}
+	for element := range rest.All() {
+		result = result.With(element)
+	}
+	return result
+}
+
+// Diff compares s with a later version and returns the elements added in
+// other and the elements removed from s. When both sets derive from the
+// same original set, subtrees they still share are skipped entirely, so the
+// cost is proportional to the amount of change rather than the set size.
+func (s *PersistentSet[T]) Diff(other *PersistentSet[T]) (added, removed *PersistentSet[T]) {
+	added, removed = s.derive(&hamtNode[T]{}, 0), s.derive(&hamtNode[T]{}, 0)
+	onlyOther := func(v T) { added = added.With(v) }
+	onlyS := func(v T) { removed = removed.With(v) }
+
+	if s.hash == other.hash {
+		hamtDiff(s.trie(), other.trie(), 0, onlyS, onlyOther)
+		return added, removed
+	}
+	for element := range s.All() {
+		if !other.Contains(element) {
+			onlyS(element)
+		}
+	}
+	for element := range other.All() {
+		if !s.Contains(element) {
+			onlyOther(element)
+		}
+	}
+	return added, removed
+}
+
+// Equal reports whether both sets contain the same elements.
+func (s *PersistentSet[T]) Equal(other *PersistentSet[T]) bool {
+	if s.size != other.size {
+		return false
+	}
+	if s.root == other.root {
+		return true
+	}
+	for element := range s.All() {
+		if !other.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *hamtNode[T]) slot(hash uint64, shift uint) (bit uint32, pos int) {
+	bit = 1 << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[T]) contains(hash uint64, value T, shift uint) bool {
+	for {
+		if shift >= 64 {
+			for _, e := range n.entries {
+				if e.value == value {
+					return true
+				}
+			}
+			return false
+		}
+		bit, pos := n.slot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := n.entries[pos]
+		if e.child == nil {
+			return e.value == value
+		}
+		n, shift = e.child, shift+hamtBits
+	}
+}
+
+// insert returns a copy of n with leaf added, or n itself if leaf's value
+// is already present.
+func (n *hamtNode[T]) insert(leaf hamtEntry[T], shift uint) (*hamtNode[T], bool) {
+	if shift >= 64 {
+		for _, e := range n.entries {
+			if e.value == leaf.value {
+				return n, false
+			}
+		}
+		return &hamtNode[T]{entries: append(n.entries[:len(n.entries):len(n.entries)], leaf)}, true
+	}
+
+	bit, pos := n.slot(leaf.hash, shift)
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry[T], len(n.entries)+1)
+		copy(entries, n.entries[:pos])
+		entries[pos] = leaf
+		copy(entries[pos+1:], n.entries[pos:])
+		return &hamtNode[T]{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+
+	e := n.entries[pos]
+	var replacement hamtEntry[T]
+	switch {
+	case e.child != nil:
+		child, added := e.child.insert(leaf, shift+hamtBits)
+		if !added {
+			return n, false
+		}
+		replacement = hamtEntry[T]{child: child}
+	case e.value == leaf.value:
+		return n, false
+	default:
+		replacement = hamtEntry[T]{child: hamtPair(e, leaf, shift+hamtBits)}
+	}
+	return n.replace(pos, replacement), true
+}
+
+// hamtPair builds the subtree holding two leaves whose hashes agree on all
+// bits below shift.
+func hamtPair[T comparable](a, b hamtEntry[T], shift uint) *hamtNode[T] {
+	if shift >= 64 {
+		return &hamtNode[T]{entries: []hamtEntry[T]{a, b}}
+	}
+	ia, ib := (a.hash>>shift)&hamtMask, (b.hash>>shift)&hamtMask
+	if ia == ib {
+		return &hamtNode[T]{
+			bitmap:  1 << ia,
+			entries: []hamtEntry[T]{{child: hamtPair(a, b, shift+hamtBits)}},
+		}
+	}
+	if ia > ib {
+		a, b = b, a
+	}
+	return &hamtNode[T]{bitmap: 1<<ia | 1<<ib, entries: []hamtEntry[T]{a, b}}
+}
+
+// remove returns a copy of n without value, or n itself if value is absent.
+// Subtrees left holding a single leaf are collapsed into that leaf.
+func (n *hamtNode[T]) remove(hash uint64, value T, shift uint) (*hamtNode[T], bool) {
+	if shift >= 64 {
+		for i, e := range n.entries {
+			if e.value == value {
+				entries := make([]hamtEntry[T], 0, len(n.entries)-1)
+				entries = append(entries, n.entries[:i]...)
+				entries = append(entries, n.entries[i+1:]...)
+				return &hamtNode[T]{entries: entries}, true
+			}
+		}
+		return n, false
+	}
+
+	bit, pos := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	e := n.entries[pos]
+	if e.child == nil {
+		if e.value != value {
+			return n, false
+		}
+		entries := make([]hamtEntry[T], 0, len(n.entries)-1)
+		entries = append(entries, n.entries[:pos]...)
+		entries = append(entries, n.entries[pos+1:]...)
+		return &hamtNode[T]{bitmap: n.bitmap &^ bit, entries: entries}, true
+	}
+
+	child, removed := e.child.remove(hash, value, shift+hamtBits)
+	if !removed {
+		return n, false
+	}
+	if len(child.entries) == 1 && child.entries[0].child == nil {
+		return n.replace(pos, child.entries[0]), true
+	}
+	return n.replace(pos, hamtEntry[T]{child: child}), true
+}
+
+// replace returns a copy of n with the entry at pos swapped for e.
+func (n *hamtNode[T]) replace(pos int, e hamtEntry[T]) *hamtNode[T] {
+	entries := make([]hamtEntry[T], len(n.entries))
+	copy(entries, n.entries)
+	entries[pos] = e
+	return &hamtNode[T]{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode[T]) walk(yield func(T) bool) bool {
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.walk(yield) {
+				return false
+			}
+		} else if !yield(e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// hamtDiff reports the elements only in a to onlyA and the elements only in
+// b to onlyB. a and b must be built with the same hash function and sit at
+// the same shift. Shared subtrees are skipped.
+func hamtDiff[T comparable](a, b *hamtNode[T], shift uint, onlyA, onlyB func(T)) {
+	if a == b {
+		return
+	}
+	if shift >= 64 {
+		for _, e := range a.entries {
+			if !b.contains(e.hash, e.value, shift) {
+				onlyA(e.value)
+			}
+		}
+		for _, e := range b.entries {
+			if !a.contains(e.hash, e.value, shift) {
+				onlyB(e.value)
+			}
+		}
+		return
+	}
+
+	for i := uint(0); i <= hamtMask; i++ {
+		bit := uint32(1) << i
+		inA, inB := a.bitmap&bit != 0, b.bitmap&bit != 0
+		var ea, eb hamtEntry[T]
+		if inA {
+			ea = a.entries[bits.OnesCount32(a.bitmap&(bit-1))]
+		}
+		if inB {
+			eb = b.entries[bits.OnesCount32(b.bitmap&(bit-1))]
+		}
+
+		switch {
+		case !inA && !inB:
+		case !inB:
+			ea.each(onlyA)
+		case !inA:
+			eb.each(onlyB)
+		case ea.child != nil && eb.child != nil:
+			hamtDiff(ea.child, eb.child, shift+hamtBits, onlyA, onlyB)
+		case ea.child == nil && eb.child == nil:
+			if ea.value != eb.value {
+				onlyA(ea.value)
+				onlyB(eb.value)
+			}
+		case ea.child == nil:
+			hamtDiffLeaf(ea, eb.child, onlyA, onlyB)
+		default:
+			hamtDiffLeaf(eb, ea.child, onlyB, onlyA)
+		}
+	}
+}
+
+// hamtDiffLeaf compares a single leaf against a subtree.
+func hamtDiffLeaf[T comparable](leaf hamtEntry[T], tree *hamtNode[T], onlyLeaf, onlyTree func(T)) {
+	found := false
+	tree.walk(func(v T) bool {
+		if v == leaf.value {
+			found = true
+		} else {
+			onlyTree(v)
+		}
+		return true
+	})
+	if !found {
+		onlyLeaf(leaf.value)
+	}
+}
+
+func (e hamtEntry[T]) each(f func(T)) {
+	if e.child == nil {
+		f(e.value)
+		return
+	}
+	e.child.walk(func(v T) bool {
+		f(v)
+		return true
+	})
+}
+
+// CheckInvariants verifies the trie: every leaf is stored under the slots
+// its hash selects with the hash it has now, bitmaps match their entries,
+// subtrees are neither empty nor a lone leaf, and the size matches the
+// number of leaves. It is meant for tests.
+func (s *PersistentSet[T]) CheckInvariants() error {
+	count := 0
+	if err := s.trie().check(s.hasher(), 0, 0, true, &count); err != nil {
+		return fmt.Errorf("PersistentSet: %w", err)
+	}
+	if count != s.size {
+		return fmt.Errorf("PersistentSet: size is %d but %d elements are stored", s.size, count)
+	}
+	return nil
+}
+
+// check verifies the subtree n found at shift, whose leaves must all have
+// prefix as the low shift bits of their hash.
+func (n *hamtNode[T]) check(hash Hasher[T], shift uint, prefix uint64, root bool, count *int) error {
+	if !root && (len(n.entries) == 0 || len(n.entries) == 1 && n.entries[0].child == nil) {
+		return fmt.Errorf("subtree at shift %d holds %d entries", shift, len(n.entries))
+	}
+	if shift >= 64 {
+		for i, e := range n.entries {
+			if e.child != nil || e.hash != prefix || hash(e.value) != e.hash {
+				return fmt.Errorf("collision bucket entry %d has the wrong hash", i)
+			}
+			for _, other := range n.entries[:i] {
+				if other.value == e.value {
+					return fmt.Errorf("collision bucket holds %v twice", e.value)
+				}
+			}
+			*count++
+		}
+		return nil
+	}
+
+	if bits.OnesCount32(n.bitmap) != len(n.entries) {
+		return fmt.Errorf("bitmap at shift %d has %d slots for %d entries", shift, bits.OnesCount32(n.bitmap), len(n.entries))
+	}
+	bitmap := n.bitmap
+	for _, e := range n.entries {
+		slot := uint64(bits.TrailingZeros32(bitmap))
+		bitmap &= bitmap - 1
+		slotPrefix := prefix | slot<<shift
+		if e.child != nil {
+			if err := e.child.check(hash, shift+hamtBits, slotPrefix, false, count); err != nil {
+				return err
+			}
+			continue
+		}
+		mask := uint64(1)<<min(shift+hamtBits, 64) - 1
+		if e.hash != hash(e.value) || e.hash&mask != slotPrefix {
+			return fmt.Errorf("leaf %v is stored under the wrong slot", e.value)
+		}
+		*count++
+	}
+	return nil
+}
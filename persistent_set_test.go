@@ -0,0 +1,325 @@
+package gocontainers
+
+import (
+	"maps"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collidingHasher maps every value into one of four hashes, forcing deep
+// tries and full collision buckets.
+func collidingHasher(v int) uint64 {
+	return uint64(v%4) * 0x9e3779b97f4a7c15
+}
+
+func TestPersistentSetEmpty(t *testing.T) {
+	s := NewPersistentSet[int]()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+	assert.False(t, s.Contains(1))
+	assert.Empty(t, s.ToSlice())
+	assert.Same(t, s, s.Without(1))
+}
+
+func TestPersistentSetWithWithout(t *testing.T) {
+	s0 := NewPersistentSet[string]()
+	s1 := s0.With("a")
+	s2 := s1.With("b")
+	s3 := s2.Without("a")
+
+	assert.True(t, s2.Contains("a"))
+	assert.True(t, s2.Contains("b"))
+	assert.Equal(t, 2, s2.Size())
+	assert.ElementsMatch(t, []string{"b"}, s3.ToSlice())
+
+	// old versions are unchanged
+	assert.ElementsMatch(t, []string{"a"}, s1.ToSlice())
+	assert.True(t, s0.IsEmpty())
+
+	// no-op updates return the receiver
+	assert.Same(t, s2, s2.With("a"))
+	assert.Same(t, s3, s3.Without("zzz"))
+}
+
+func TestPersistentSetCollisions(t *testing.T) {
+	s := NewPersistentSetWithHasher(Hasher[int](collidingHasher))
+	for i := 0; i < 40; i++ {
+		s = s.With(i)
+	}
+	assert.Equal(t, 40, s.Size())
+	for i := 0; i < 40; i++ {
+		assert.True(t, s.Contains(i))
+	}
+	assert.False(t, s.Contains(40))
+
+	for i := 0; i < 40; i += 2 {
+		s = s.Without(i)
+	}
+	assert.Equal(t, 20, s.Size())
+	for i := 0; i < 40; i++ {
+		assert.Equal(t, i%2 == 1, s.Contains(i))
+	}
+}
+
+func TestPersistentSetRandomOps(t *testing.T) {
+	hashers := map[string]Hasher[int]{
+		"maphash":   NewMaphashHasher[int](),
+		"colliding": collidingHasher,
+	}
+	for name, h := range hashers {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			s := NewPersistentSetWithHasher(h)
+			model := map[int]bool{}
+			var versions []*PersistentSet[int]
+			var models []map[int]bool
+
+			for i := 0; i < 3000; i++ {
+				v := rng.IntN(200)
+				if rng.IntN(3) == 0 {
+					s = s.Without(v)
+					delete(model, v)
+				} else {
+					s = s.With(v)
+					model[v] = true
+				}
+				if i%300 == 0 {
+					snapshot := make(map[int]bool, len(model))
+					for k := range model {
+						snapshot[k] = true
+					}
+					versions = append(versions, s)
+					models = append(models, snapshot)
+				}
+			}
+
+			assert.Equal(t, len(model), s.Size())
+			for v := 0; v < 200; v++ {
+				assert.Equal(t, model[v], s.Contains(v))
+			}
+			// snapshots still match their models
+			for i, version := range versions {
+				assert.Equal(t, len(models[i]), version.Size())
+				for _, v := range version.ToSlice() {
+					assert.True(t, models[i][v])
+				}
+			}
+			// structural Diff agrees with the models
+			for i := 1; i < len(versions); i++ {
+				added, removed := versions[i-1].Diff(versions[i])
+				for v := 0; v < 200; v++ {
+					assert.Equal(t, models[i][v] && !models[i-1][v], added.Contains(v))
+					assert.Equal(t, models[i-1][v] && !models[i][v], removed.Contains(v))
+				}
+			}
+		})
+	}
+}
+
+func TestPersistentSetUnion(t *testing.T) {
+	a := NewPersistentSet[int]().With(1).With(2)
+	b := NewPersistentSet[int]().With(2).With(3).With(4)
+
+	u := a.Union(b)
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, u.ToSlice())
+	assert.ElementsMatch(t, []int{1, 2}, a.ToSlice())
+	assert.ElementsMatch(t, []int{2, 3, 4}, b.ToSlice())
+
+	derived := a.With(5)
+	assert.ElementsMatch(t, []int{1, 2, 5}, a.Union(derived).ToSlice())
+}
+
+func TestPersistentSetDiff(t *testing.T) {
+	for _, h := range []Hasher[int]{NewMaphashHasher[int](), collidingHasher} {
+		old := NewPersistentSetWithHasher(h)
+		for i := 0; i < 100; i++ {
+			old = old.With(i)
+		}
+		cur := old.Without(3).Without(50).With(200).With(201)
+
+		added, removed := old.Diff(cur)
+		assert.ElementsMatch(t, []int{200, 201}, added.ToSlice())
+		assert.ElementsMatch(t, []int{3, 50}, removed.ToSlice())
+
+		added, removed = cur.Diff(old)
+		assert.ElementsMatch(t, []int{3, 50}, added.ToSlice())
+		assert.ElementsMatch(t, []int{200, 201}, removed.ToSlice())
+
+		added, removed = old.Diff(old)
+		assert.True(t, added.IsEmpty())
+		assert.True(t, removed.IsEmpty())
+	}
+}
+
+func TestPersistentSetDiffUnrelated(t *testing.T) {
+	a := NewPersistentSet[string]().With("x").With("y")
+	b := NewPersistentSet[string]().With("y").With("z")
+
+	added, removed := a.Diff(b)
+	assert.ElementsMatch(t, []string{"z"}, added.ToSlice())
+	assert.ElementsMatch(t, []string{"x"}, removed.ToSlice())
+}
+
+func TestPersistentSetEqual(t *testing.T) {
+	a := NewPersistentSet[int]().With(1).With(2)
+	b := NewPersistentSet[int]().With(2).With(1)
+	assert.True(t, a.Equal(b))
+	assert.True(t, a.Equal(a.With(1)))
+	assert.False(t, a.Equal(a.With(3)))
+	assert.False(t, a.Equal(a.Without(1).With(3)))
+}
+
+func TestPersistentSetAllStopsEarly(t *testing.T) {
+	s := NewPersistentSet[int]().With(1).With(2).With(3)
+	count := 0
+	for range s.All() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestPersistentSetZeroValue(t *testing.T) {
+	var zero PersistentSet[string]
+	assert.True(t, zero.IsEmpty())
+	assert.False(t, zero.Contains("a"))
+	assert.Same(t, &zero, zero.Without("a"))
+	assert.Empty(t, zero.ToSlice())
+	assert.NoError(t, zero.CheckInvariants())
+
+	a := zero.With("a").With("b")
+	assert.True(t, a.Contains("a"))
+	assert.True(t, a.Contains("b"))
+	assert.NoError(t, a.CheckInvariants())
+
+	// versions grown from different zero values hash alike
+	var other PersistentSet[string]
+	b := other.With("b").With("c")
+	added, removed := a.Diff(b)
+	assert.Equal(t, []string{"c"}, added.ToSlice())
+	assert.Equal(t, []string{"a"}, removed.ToSlice())
+	assert.True(t, a.Equal(b.Without("c").With("a")))
+	assert.True(t, zero.Equal(a.Without("a").Without("b")))
+
+	u := a.Union(NewPersistentSet[string]().With("d"))
+	assert.ElementsMatch(t, []string{"a", "b", "d"}, u.ToSlice())
+}
+
+func TestPersistentSetCheckInvariantsDetectsCorruption(t *testing.T) {
+	s := NewPersistentSetWithHasher(mixHasher).With(1).With(2)
+	assert.NoError(t, s.CheckInvariants())
+
+	s.size = 3
+	assert.Error(t, s.CheckInvariants())
+	s.size = 2
+
+	s.root.entries[0].hash ^= 1 << 40
+	assert.Error(t, s.CheckInvariants())
+}
+
+// FuzzPersistentSet derives a tree of versions from a random sequence of
+// operations, one per input byte, each applied to an earlier version, and
+// checks them against map models.
+func FuzzPersistentSet(f *testing.F) {
+	f.Add(false, []byte{0, 8, 16, 1, 25, 2, 3})
+	f.Add(true, []byte{0, 8, 16, 24, 1, 9, 2, 3})
+	f.Fuzz(func(t *testing.T, collide bool, ops []byte) {
+		hash := mixHasher
+		if collide {
+			hash = collidingHasher
+		}
+		versions := []*PersistentSet[int]{NewPersistentSetWithHasher(hash)}
+		models := []map[int]bool{{}}
+		check := func(i int, op byte, j int) {
+			s := versions[j]
+			if err := s.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d), version %d: %v", i, op, j, err)
+			}
+			if s.Size() != len(models[j]) {
+				t.Fatalf("after op %d (%d): version %d Size = %d, want %d", i, op, j, s.Size(), len(models[j]))
+			}
+			for v := range 32 {
+				if s.Contains(v) != models[j][v] {
+					t.Fatalf("after op %d (%d): version %d Contains(%d) = %t", i, op, j, v, !models[j][v])
+				}
+			}
+		}
+		for i, op := range ops {
+			k := int(op>>2) % len(versions)
+			s, model := versions[k], models[k]
+			v := int(op >> 3)
+			switch op % 4 {
+			case 0, 1:
+				next := maps.Clone(model)
+				next[v] = true
+				versions = append(versions, s.With(v))
+				models = append(models, next)
+			case 2:
+				next := maps.Clone(model)
+				delete(next, v)
+				versions = append(versions, s.Without(v))
+				models = append(models, next)
+			case 3:
+				j := int(op>>4) % len(versions)
+				if got, want := s.Equal(versions[j]), maps.Equal(model, models[j]); got != want {
+					t.Fatalf("op %d: version %d Equal version %d = %t, want %t", i, k, j, got, want)
+				}
+			}
+
+			// the newest version, and one older version to show that deriving
+			// new versions left it intact; checking every version after every
+			// op would make the target quadratic
+			check(i, op, len(versions)-1)
+			check(i, op, (i*31+int(op))%len(versions))
+		}
+	})
+}
+
+func benchPersistentSetWithWithout[T comparable](b *testing.B, values []T) {
+	for b.Loop() {
+		s := NewPersistentSet[T]()
+		for _, v := range values {
+			s = s.With(v)
+		}
+		for _, v := range values {
+			s = s.Without(v)
+		}
+	}
+}
+
+func benchPersistentSetContains[T comparable](b *testing.B, values []T) {
+	s := NewPersistentSet[T]()
+	for _, v := range values {
+		s = s.With(v)
+	}
+	for b.Loop() {
+		for _, v := range values {
+			s.Contains(v)
+		}
+	}
+}
+
+func benchPersistentSetIterate[T comparable](b *testing.B, values []T) {
+	s := NewPersistentSet[T]()
+	for _, v := range values {
+		s = s.With(v)
+	}
+	for b.Loop() {
+		for range s.All() {
+		}
+	}
+}
+
+func BenchmarkPersistentSetWithWithout(b *testing.B) {
+	runBench(b, benchPersistentSetWithWithout[int], benchPersistentSetWithWithout[string])
+}
+
+func BenchmarkPersistentSetContains(b *testing.B) {
+	runBench(b, benchPersistentSetContains[int], benchPersistentSetContains[string])
+}
+
+func BenchmarkPersistentSetIterate(b *testing.B) {
+	runBench(b, benchPersistentSetIterate[int], benchPersistentSetIterate[string])
+}
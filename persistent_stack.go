@@ -0,0 +1,101 @@
+package gocontainers
+
+import (
+	"fmt"
+	"iter"
+)
+
+// PersistentStack is an immutable LIFO stack. Push and Pop never modify the
+// receiver; they return a new version that shares the rest of the stack with
+// the old one, so both take O(1) time and memory and every earlier version
+// stays valid. A PersistentStack is safe for concurrent use.
+type PersistentStack[T any] struct {
+	top  T
+	rest *PersistentStack[T] // nil only for the empty stack
+	size int
+}
+
+// NewPersistentStack returns an empty PersistentStack.
+func NewPersistentStack[T any]() *PersistentStack[T] {
+	return &PersistentStack[T]{}
+}
+
+// Push returns a new version of the stack with element on top.
+func (s *PersistentStack[T]) Push(element T) *PersistentStack[T] {
+	return &PersistentStack[T]{top: element, rest: s, size: s.size + 1}
+}
+
+// Pop returns the top element and the version of the stack without it.
+// It panics if the stack is empty.
+func (s *PersistentStack[T]) Pop() (T, *PersistentStack[T]) {
+	if s.size == 0 {
+		panic("Pop from empty stack")
+	}
+	return s.top, s.rest
+}
+
+// Peek returns the top element. It panics if the stack is empty.
+func (s *PersistentStack[T]) Peek() T {
+	if s.size == 0 {
+		panic("Peek from empty stack")
+	}
+	return s.top
+}
+
+func (s *PersistentStack[T]) Size() int {
+	return s.size
+}
+
+func (s *PersistentStack[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// Iter returns an iterator over the elements from top to bottom.
+func (s *PersistentStack[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for cur := s; cur.size > 0; cur = cur.rest {
+			if !yield(cur.top) {
+				return
+			}
+		}
+	}
+}
+
+// EqualFunc reports whether both stacks hold the same elements in the same
+// order, comparing elements with eq. Shared tails are recognised and not
+// compared element by element.
+func (s *PersistentStack[T]) EqualFunc(other *PersistentStack[T], eq func(a, b T) bool) bool {
+	if s.size != other.size {
+		return false
+	}
+	for a, b := s, other; a != b; a, b = a.rest, b.rest {
+		if a.size == 0 {
+			return true // both reached distinct empty stacks
+		}
+		if !eq(a.top, b.top) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualPersistentStacks reports whether a and b hold equal elements in the
+// same order.
+func EqualPersistentStacks[T comparable](a, b *PersistentStack[T]) bool {
+	return a.EqualFunc(b, func(x, y T) bool { return x == y })
+}
+
+// CheckInvariants verifies that every version below s is one element
+// smaller than the one above it and that the chain ends in an empty stack.
+// It is meant for tests.
+func (s *PersistentStack[T]) CheckInvariants() error {
+	for cur := s; cur.size > 0; cur = cur.rest {
+		if cur.rest == nil {
+			return fmt.Errorf("PersistentStack: size %d version has no rest", cur.size)
+		}
+		if cur.rest.size != cur.size-1 {
+			return fmt.Errorf("PersistentStack: size %d version sits on a size %d version", cur.size, cur.rest.size)
+		}
+	}
+	return nil
+}
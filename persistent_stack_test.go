@@ -0,0 +1,174 @@
+package gocontainers
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentStackEmpty(t *testing.T) {
+	s := NewPersistentStack[int]()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+	assert.Empty(t, slices.Collect(s.Iter()))
+	assert.Panics(t, func() { s.Pop() })
+	assert.Panics(t, func() { s.Peek() })
+}
+
+func TestPersistentStackPushPop(t *testing.T) {
+	s0 := NewPersistentStack[int]()
+	s1 := s0.Push(1)
+	s2 := s1.Push(2)
+	s3 := s2.Push(3)
+
+	assert.Equal(t, 3, s3.Size())
+	assert.Equal(t, 3, s3.Peek())
+	assert.Equal(t, []int{3, 2, 1}, slices.Collect(s3.Iter()))
+
+	top, rest := s3.Pop()
+	assert.Equal(t, 3, top)
+	assert.Same(t, s2, rest)
+
+	// old versions are unchanged
+	assert.Equal(t, []int{2, 1}, slices.Collect(s2.Iter()))
+	assert.Equal(t, []int{1}, slices.Collect(s1.Iter()))
+	assert.True(t, s0.IsEmpty())
+}
+
+func TestPersistentStackBranching(t *testing.T) {
+	base := NewPersistentStack[string]().Push("a").Push("b")
+	left := base.Push("left")
+	right := base.Push("right")
+	_, popped := base.Pop()
+
+	assert.Equal(t, []string{"left", "b", "a"}, slices.Collect(left.Iter()))
+	assert.Equal(t, []string{"right", "b", "a"}, slices.Collect(right.Iter()))
+	assert.Equal(t, []string{"a"}, slices.Collect(popped.Iter()))
+	assert.Equal(t, []string{"b", "a"}, slices.Collect(base.Iter()))
+
+	// branches share the base rather than copying it
+	_, leftRest := left.Pop()
+	_, rightRest := right.Pop()
+	assert.Same(t, leftRest, rightRest)
+}
+
+func TestPersistentStackIterStopsEarly(t *testing.T) {
+	s := NewPersistentStack[int]().Push(1).Push(2).Push(3)
+	var got []int
+	for v := range s.Iter() {
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{3, 2}, got)
+}
+
+func TestPersistentStackEqual(t *testing.T) {
+	a := NewPersistentStack[int]().Push(1).Push(2)
+	b := NewPersistentStack[int]().Push(1).Push(2)
+	assert.True(t, EqualPersistentStacks(a, b))
+	assert.True(t, EqualPersistentStacks(a, a))
+	assert.True(t, EqualPersistentStacks(NewPersistentStack[int](), NewPersistentStack[int]()))
+
+	assert.False(t, EqualPersistentStacks(a, a.Push(3)))
+	assert.False(t, EqualPersistentStacks(a, NewPersistentStack[int]().Push(2).Push(1)))
+
+	// shared tail, different tops
+	assert.False(t, EqualPersistentStacks(a.Push(3), a.Push(4)))
+	assert.True(t, EqualPersistentStacks(a.Push(3), a.Push(3)))
+}
+
+func TestPersistentStackEqualFunc(t *testing.T) {
+	// elements need not be comparable
+	a := NewPersistentStack[[]byte]().Push([]byte("x")).Push([]byte("y"))
+	b := NewPersistentStack[[]byte]().Push([]byte("x")).Push([]byte("y"))
+	assert.True(t, a.EqualFunc(b, bytes.Equal))
+	assert.False(t, a.EqualFunc(NewPersistentStack[[]byte]().Push([]byte("y")).Push([]byte("x")), bytes.Equal))
+	assert.False(t, a.EqualFunc(a.Push([]byte("z")), bytes.Equal))
+
+	calls := 0
+	eq := func(x, y []byte) bool {
+		calls++
+		return bytes.Equal(x, y)
+	}
+	assert.True(t, a.Push([]byte("z")).EqualFunc(a.Push([]byte("z")), eq))
+	assert.Equal(t, 1, calls, "the shared tail is not compared")
+}
+
+func FuzzPersistentStack(f *testing.F) {
+	f.Add([]byte{0, 8, 16, 1, 25, 2})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		// every version ever made, with the contents it must keep
+		versions := []*PersistentStack[int]{NewPersistentStack[int]()}
+		models := [][]int{nil}
+		for i, op := range ops {
+			k := int(op>>2) % len(versions)
+			s, model := versions[k], models[k]
+			switch op % 4 {
+			case 0, 1:
+				v := int(op >> 2)
+				versions = append(versions, s.Push(v))
+				models = append(models, append(slices.Clip(model), v))
+			case 2:
+				if len(model) > 0 {
+					top, rest := s.Pop()
+					if want := model[len(model)-1]; top != want {
+						t.Fatalf("op %d: Pop = %d, want %d", i, top, want)
+					}
+					versions = append(versions, rest)
+					models = append(models, model[:len(model)-1])
+				}
+			case 3:
+				j := int(op>>4) % len(versions)
+				if got, want := EqualPersistentStacks(s, versions[j]), slices.Equal(model, models[j]); got != want {
+					t.Fatalf("op %d: version %d Equal version %d = %t, want %t", i, k, j, got, want)
+				}
+			}
+
+			for j, v := range versions {
+				if err := v.CheckInvariants(); err != nil {
+					t.Fatalf("after op %d (%d), version %d: %v", i, op, j, err)
+				}
+				got := slices.Collect(v.Iter())
+				slices.Reverse(got)
+				if !slices.Equal(got, models[j]) {
+					t.Fatalf("after op %d (%d): version %d = %v, want %v", i, op, j, got, models[j])
+				}
+			}
+		}
+	})
+}
+
+func benchPersistentStackPushPop[T comparable](b *testing.B, values []T) {
+	for b.Loop() {
+		s := NewPersistentStack[T]()
+		for _, v := range values {
+			s = s.Push(v)
+		}
+		for !s.IsEmpty() {
+			_, s = s.Pop()
+		}
+	}
+}
+
+func benchPersistentStackIterate[T comparable](b *testing.B, values []T) {
+	s := NewPersistentStack[T]()
+	for _, v := range values {
+		s = s.Push(v)
+	}
+	for b.Loop() {
+		for range s.Iter() {
+		}
+	}
+}
+
+func BenchmarkPersistentStackPushPop(b *testing.B) {
+	runBench(b, benchPersistentStackPushPop[int], benchPersistentStackPushPop[string])
+}
+
+func BenchmarkPersistentStackIterate(b *testing.B) {
+	runBench(b, benchPersistentStackIterate[int], benchPersistentStackIterate[string])
+}
@@ -0,0 +1,17 @@
+package gocontainers
+
+// OverflowPolicy decides what a bounded container does when an element is
+// added while it is full. Each container documents which policies it supports.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until there is room for the new element.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject refuses the new element with ErrFull.
+	OverflowReject
+	// OverflowDropLowest discards the lowest-priority element, which may be
+	// the new element itself; that is reported with ErrDropped.
+	OverflowDropLowest
+	// OverflowDropOldest discards the oldest element to make room.
+	OverflowDropOldest
+)
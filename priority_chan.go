@@ -0,0 +1,240 @@
+package gocontainers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PriorityChan is a multi-producer, multi-consumer channel that delivers
+// elements in priority order rather than in the order they were sent.
+// It is backed by a Heap and is safe for concurrent use.
+//
+// Like a Go channel, a closed PriorityChan still delivers the elements it
+// holds; Recv only reports ErrClosed once it is drained.
+type PriorityChan[T any] struct {
+	mu         sync.Mutex
+	heap       *Heap[T]
+	comparator func(a, b T) bool
+	capacity   int
+	policy     OverflowPolicy
+	closed     bool
+	changed    chan struct{} // closed and replaced on every state change
+	held       int           // elements taken by Chan views but not yet read
+}
+
+// NewPriorityChan creates a new PriorityChan ordered by comparator, which
+// should return true if element a has higher priority than element b, as for
+// NewHeap. A capacity <= 0 means unbounded. policy decides what Send does
+// when the channel is full: OverflowBlock, OverflowReject or
+// OverflowDropLowest.
+func NewPriorityChan[T any](comparator func(a, b T) bool, capacity int, policy OverflowPolicy) *PriorityChan[T] {
+	if policy != OverflowBlock && policy != OverflowReject && policy != OverflowDropLowest {
+		panic("unsupported overflow policy for PriorityChan")
+	}
+	return &PriorityChan[T]{
+		heap:       NewHeap(comparator),
+		comparator: comparator,
+		capacity:   capacity,
+		policy:     policy,
+		changed:    make(chan struct{}),
+	}
+}
+
+// broadcast wakes every goroutine waiting on the current state. Callers must
+// hold p.mu.
+func (p *PriorityChan[T]) broadcast() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// wait releases p.mu until the state changes or ctx is done, and reacquires
+// it before returning. Callers must hold p.mu.
+func (p *PriorityChan[T]) wait(ctx context.Context) error {
+	changed := p.changed
+	p.mu.Unlock()
+	defer p.mu.Lock()
+	select {
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send adds element to the channel. When the channel is full, Send blocks
+// until there is room or ctx is done (OverflowBlock), returns ErrFull
+// (OverflowReject), or discards the lowest-priority element
+// (OverflowDropLowest). If that is element itself, Send returns ErrDropped.
+// Send returns ErrClosed once the channel is closed.
+func (p *PriorityChan[T]) Send(ctx context.Context, element T) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		if p.closed {
+			return ErrClosed
+		}
+		if p.capacity <= 0 || p.heap.Len()+p.held < p.capacity {
+			p.heap.PushItem(NewItem(element))
+			p.broadcast()
+			return nil
+		}
+
+		switch p.policy {
+		case OverflowReject:
+			return ErrFull
+		case OverflowDropLowest:
+			// elements held by views are no longer droppable
+			if p.heap.Len() == 0 {
+				return ErrDropped
+			}
+			lowest := p.lowest()
+			if !p.comparator(element, lowest.val) {
+				return ErrDropped
+			}
+			p.heap.RemoveItem(lowest)
+			p.heap.PushItem(NewItem(element))
+			p.broadcast()
+			return nil
+		}
+
+		if err := p.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// lowest returns the lowest-priority item in the heap, which is always one
+// of the leaves. Callers must hold p.mu and the heap must not be empty.
+func (p *PriorityChan[T]) lowest() *Item[T] {
+	data := p.heap.data
+	lowest := data[len(data)-1]
+	for _, item := range data[len(data)/2:] {
+		if p.comparator(lowest.val, item.val) {
+			lowest = item
+		}
+	}
+	return lowest
+}
+
+// Recv removes and returns the highest-priority element, blocking until one
+// is available or ctx is done. It returns ErrClosed once the channel is
+// closed and empty.
+func (p *PriorityChan[T]) Recv(ctx context.Context) (T, error) {
+	return p.recv(ctx, false)
+}
+
+// recv is Recv. If hold is set, the element keeps its place against the
+// capacity until it is passed to release.
+func (p *PriorityChan[T]) recv(ctx context.Context, hold bool) (T, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		if p.heap.Len() > 0 {
+			item := p.heap.PopItem()
+			if hold {
+				p.held++
+			} else {
+				p.broadcast()
+			}
+			return item.val, nil
+		}
+		if p.closed {
+			var zero T
+			return zero, ErrClosed
+		}
+		if err := p.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// Close closes the channel. Pending and future Sends fail with ErrClosed;
+// receivers drain the remaining elements before getting ErrClosed.
+// Closing an already closed channel does nothing.
+func (p *PriorityChan[T]) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		p.broadcast()
+	}
+}
+
+// release ends the hold recv took on element, putting it back in the
+// channel if it was not delivered.
+func (p *PriorityChan[T]) release(element T, delivered bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.held--
+	if !delivered {
+		p.heap.PushItem(NewItem(element))
+	}
+	p.broadcast()
+}
+
+// Chan returns a receive-only Go channel view of p for use in select
+// statements. Each call starts a goroutine that forwards elements to a new
+// view until ctx is done, or until p is closed and drained, and then closes
+// the view. Cancel ctx once the view is no longer read, or the goroutine
+// leaks.
+//
+// A view competes with Recv and with other views, and each element goes to
+// exactly one of them. While waiting for a reader, the goroutine holds the
+// element it took: Recv and Len do not see it, and higher-priority elements
+// sent afterwards may overtake it. It still counts against the capacity,
+// and it is put back in the channel if ctx is done before it is read.
+func (p *PriorityChan[T]) Chan(ctx context.Context) <-chan T {
+	view := make(chan T)
+	go func() {
+		defer close(view)
+		for {
+			element, err := p.recv(ctx, true)
+			if err != nil {
+				return
+			}
+			select {
+			case view <- element:
+				p.release(element, true)
+			case <-ctx.Done():
+				p.release(element, false)
+				return
+			}
+		}
+	}()
+	return view
+}
+
+// Len returns the number of elements waiting in the channel.
+func (p *PriorityChan[T]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.heap.Len()
+}
+
+// CheckInvariants verifies the underlying heap, and that the elements in
+// the channel and those held by views fit the capacity. It is meant for
+// tests.
+func (p *PriorityChan[T]) CheckInvariants() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.heap.CheckInvariants(); err != nil {
+		return fmt.Errorf("PriorityChan: %w", err)
+	}
+	if p.held < 0 {
+		return fmt.Errorf("PriorityChan: %d elements held", p.held)
+	}
+	if p.capacity > 0 && p.heap.Len()+p.held > p.capacity {
+		return fmt.Errorf("PriorityChan: %d elements and %d held exceed capacity %d", p.heap.Len(), p.held, p.capacity)
+	}
+	return nil
+}
+
+// Cap returns the capacity of the channel, or 0 if it is unbounded.
+func (p *PriorityChan[T]) Cap() int {
+	if p.capacity <= 0 {
+		return 0
+	}
+	return p.capacity
+}
@@ -0,0 +1,337 @@
+package gocontainers
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func maxFirst(a, b int) bool { return a > b }
+
+func TestPriorityChanOrder(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 0, OverflowBlock)
+	for _, v := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		assert.NoError(t, p.Send(ctx, v))
+	}
+	assert.Equal(t, 8, p.Len())
+	assert.Equal(t, 0, p.Cap())
+
+	var got []int
+	for p.Len() > 0 {
+		v, err := p.Recv(ctx)
+		assert.NoError(t, err)
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{9, 6, 5, 4, 3, 2, 1, 1}, got)
+}
+
+func TestPriorityChanRecvBlocksUntilSend(t *testing.T) {
+	p := NewPriorityChan(maxFirst, 0, OverflowBlock)
+	result := make(chan int)
+	go func() {
+		v, err := p.Recv(context.Background())
+		assert.NoError(t, err)
+		result <- v
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, p.Send(context.Background(), 42))
+	assert.Equal(t, 42, <-result)
+}
+
+func TestPriorityChanRecvContext(t *testing.T) {
+	p := NewPriorityChan(maxFirst, 0, OverflowBlock)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := p.Recv(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPriorityChanBlockWhenFull(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 2, OverflowBlock)
+	assert.Equal(t, 2, p.Cap())
+	assert.NoError(t, p.Send(ctx, 1))
+	assert.NoError(t, p.Send(ctx, 2))
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Send(timeout, 3), context.DeadlineExceeded)
+
+	sent := make(chan error)
+	go func() { sent <- p.Send(ctx, 3) }()
+	time.Sleep(10 * time.Millisecond)
+	v, err := p.Recv(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+	assert.NoError(t, <-sent)
+	assert.Equal(t, 2, p.Len())
+}
+
+func TestPriorityChanReject(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 1, OverflowReject)
+	assert.NoError(t, p.Send(ctx, 1))
+	assert.ErrorIs(t, p.Send(ctx, 2), ErrFull)
+	assert.Equal(t, 1, p.Len())
+}
+
+func TestPriorityChanDropLowest(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 3, OverflowDropLowest)
+	for _, v := range []int{5, 1, 7} {
+		assert.NoError(t, p.Send(ctx, v))
+	}
+
+	assert.NoError(t, p.Send(ctx, 6))             // evicts 1
+	assert.ErrorIs(t, p.Send(ctx, 0), ErrDropped) // lowest itself
+	assert.ErrorIs(t, p.Send(ctx, 5), ErrDropped) // ties keep the older element
+	assert.Equal(t, 3, p.Len())
+
+	var got []int
+	for p.Len() > 0 {
+		v, _ := p.Recv(ctx)
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{7, 6, 5}, got)
+}
+
+func TestPriorityChanClose(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 0, OverflowBlock)
+	assert.NoError(t, p.Send(ctx, 1))
+	assert.NoError(t, p.Send(ctx, 2))
+
+	p.Close()
+	p.Close()
+	assert.ErrorIs(t, p.Send(ctx, 3), ErrClosed)
+
+	// remaining elements are still delivered
+	v, err := p.Recv(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+	v, err = p.Recv(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	_, err = p.Recv(ctx)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestPriorityChanCloseWakesWaiters(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 1, OverflowBlock)
+	assert.NoError(t, p.Send(ctx, 1))
+
+	sendErr := make(chan error)
+	go func() { sendErr <- p.Send(ctx, 2) }()
+	time.Sleep(10 * time.Millisecond)
+	p.Close()
+	assert.ErrorIs(t, <-sendErr, ErrClosed)
+
+	empty := NewPriorityChan(maxFirst, 0, OverflowBlock)
+	recvErr := make(chan error)
+	go func() {
+		_, err := empty.Recv(ctx)
+		recvErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	empty.Close()
+	assert.ErrorIs(t, <-recvErr, ErrClosed)
+}
+
+func TestPriorityChanSelectView(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := NewPriorityChan(maxFirst, 0, OverflowBlock)
+	view := p.Chan(ctx)
+
+	for _, v := range []int{1, 2, 3} {
+		assert.NoError(t, p.Send(ctx, v))
+	}
+	p.Close()
+
+	var got []int
+	for {
+		select {
+		case v, ok := <-view:
+			if !ok {
+				assert.ElementsMatch(t, []int{1, 2, 3}, got)
+				return
+			}
+			got = append(got, v)
+		case <-time.After(time.Second):
+			t.Fatal("view was not closed")
+		}
+	}
+}
+
+func TestPriorityChanViewCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPriorityChan(maxFirst, 1, OverflowReject)
+	require.NoError(t, p.Send(ctx, 1))
+	view := p.Chan(ctx)
+
+	// the view takes the element but it keeps its place against the capacity
+	require.Eventually(t, func() bool { return p.Len() == 0 }, time.Second, time.Millisecond)
+	assert.ErrorIs(t, p.Send(ctx, 2), ErrFull)
+	assert.NoError(t, p.CheckInvariants())
+
+	// cancelling stops the view and puts the element back
+	cancel()
+	require.Eventually(t, func() bool { return p.Len() == 1 }, time.Second, time.Millisecond)
+	_, ok := <-view
+	assert.False(t, ok, "view is closed")
+	assert.NoError(t, p.CheckInvariants())
+	v, err := p.Recv(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestPriorityChanUnsupportedPolicy(t *testing.T) {
+	assert.Panics(t, func() { NewPriorityChan(maxFirst, 1, OverflowPolicy(99)) })
+}
+
+func TestPriorityChanConcurrent(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 1000
+	ctx := context.Background()
+	p := NewPriorityChan(maxFirst, 16, OverflowBlock)
+
+	var producersDone sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		producersDone.Add(1)
+		go func(i int) {
+			defer producersDone.Done()
+			for j := 0; j < perProducer; j++ {
+				assert.NoError(t, p.Send(ctx, i*perProducer+j))
+			}
+		}(i)
+	}
+
+	var mu sync.Mutex
+	var got []int
+	var consumersDone sync.WaitGroup
+	for i := 0; i < consumers; i++ {
+		consumersDone.Add(1)
+		go func() {
+			defer consumersDone.Done()
+			for {
+				v, err := p.Recv(ctx)
+				if err != nil {
+					assert.ErrorIs(t, err, ErrClosed)
+					return
+				}
+				mu.Lock()
+				got = append(got, v)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	producersDone.Wait()
+	p.Close()
+	consumersDone.Wait()
+
+	assert.Len(t, got, producers*perProducer)
+	sort.Ints(got)
+	for i, v := range got {
+		assert.Equal(t, i, v)
+	}
+}
+
+// FuzzPriorityChan applies a random sequence of operations, one per input
+// byte, to a PriorityChan and a sorted slice model and checks that they
+// agree after every step. Operations use a cancelled context, so any that
+// would block return at once.
+func FuzzPriorityChan(f *testing.F) {
+	f.Add(uint8(3), uint8(0), []byte{4, 8, 12, 16, 2, 0, 3, 4})
+	f.Add(uint8(2), uint8(1), []byte{4, 8, 12, 2, 2, 2, 0})
+	f.Add(uint8(2), uint8(2), []byte{8, 4, 12, 0, 2, 3, 2, 2})
+	f.Fuzz(func(t *testing.T, capacity, policy uint8, ops []byte) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		policies := []OverflowPolicy{OverflowBlock, OverflowReject, OverflowDropLowest}
+		p := NewPriorityChan(maxFirst, int(capacity%8), policies[int(policy)%len(policies)])
+		var model []int // ascending, so the highest priority is last
+		closed := false
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 4 {
+			case 0, 1:
+				err := p.Send(ctx, v)
+				var want error
+				switch {
+				case closed:
+					want = ErrClosed
+				case p.Cap() == 0 || len(model) < p.Cap():
+					model = append(model, v)
+				case p.policy == OverflowBlock:
+					want = context.Canceled
+				case p.policy == OverflowReject:
+					want = ErrFull
+				case v > model[0]:
+					model = append(model[1:], v)
+				default:
+					want = ErrDropped
+				}
+				slices.Sort(model)
+				if err != want {
+					t.Fatalf("op %d: Send(%d) = %v, want %v", i, v, err, want)
+				}
+			case 2:
+				got, err := p.Recv(ctx)
+				switch {
+				case len(model) > 0:
+					want := model[len(model)-1]
+					model = model[:len(model)-1]
+					if err != nil || got != want {
+						t.Fatalf("op %d: Recv = %d, %v, want %d", i, got, err, want)
+					}
+				case closed:
+					if err != ErrClosed {
+						t.Fatalf("op %d: Recv on closed channel = %v", i, err)
+					}
+				default:
+					if err != context.Canceled {
+						t.Fatalf("op %d: Recv on empty channel = %v", i, err)
+					}
+				}
+			case 3:
+				if v%4 == 0 {
+					p.Close()
+					closed = true
+				}
+			}
+
+			if err := p.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), p.Len(), "after op %d (%d)", i, op)
+		}
+	})
+}
+
+func benchPriorityChanSendRecv[T cmp.Ordered](b *testing.B, values []T) {
+	ctx := context.Background()
+	p := NewPriorityChan(cmp.Less[T], len(values), OverflowReject)
+	for b.Loop() {
+		for _, v := range values {
+			_ = p.Send(ctx, v)
+		}
+		for range values {
+			_, _ = p.Recv(ctx)
+		}
+	}
+}
+
+func BenchmarkPriorityChanSendRecv(b *testing.B) {
+	runBench(b, benchPriorityChanSendRecv[int], benchPriorityChanSendRecv[string])
+}
@@ -0,0 +1,177 @@
+package gocontainers
+
+import "fmt"
+
+// Ring is a circular doubly linked list with a cursor. The back of the ring
+// links to the front, so Node.Next and Node.Prev never return nil for a
+// node in a ring and a cursor can cycle through the elements forever.
+type Ring[T any] struct {
+	cur   *Node[T]
+	size  int
+	token *listToken // carried by the nodes in the ring, see DLL
+}
+
+func NewRing[T any]() *Ring[T] {
+	return &Ring[T]{cur: nil, size: 0}
+}
+
+// Len returns the number of nodes in the ring.
+func (r *Ring[T]) Len() int {
+	return r.size
+}
+
+func (r *Ring[T]) IsEmpty() bool {
+	return r.cur == nil
+}
+
+// Clear removes all elements from the Ring
+func (r *Ring[T]) Clear() {
+	if r.token != nil {
+		r.token.retired = true
+		r.token = nil
+	}
+	r.cur = nil
+	r.size = 0
+}
+
+// Current returns the node under the cursor, or nil if the ring is empty.
+func (r *Ring[T]) Current() *Node[T] {
+	return r.cur
+}
+
+// Advance moves the cursor n nodes forward, or backward if n is negative,
+// and returns the new current node. It returns nil if the ring is empty.
+func (r *Ring[T]) Advance(n int) *Node[T] {
+	if r.cur == nil {
+		return nil
+	}
+	n %= r.size
+	if n < 0 {
+		n += r.size
+	}
+	// walk whichever way round is shorter
+	if n <= r.size/2 {
+		for ; n > 0; n-- {
+			r.cur = r.cur.next
+		}
+	} else {
+		for n = r.size - n; n > 0; n-- {
+			r.cur = r.cur.prev
+		}
+	}
+	return r.cur
+}
+
+// InsertAfter links node into the ring just after the cursor. If the ring
+// is empty, node becomes the current node. It panics if node is already in
+// a list or ring.
+func (r *Ring[T]) InsertAfter(node *Node[T]) {
+	r.link(node)
+	if r.cur == nil {
+		r.init(node)
+		return
+	}
+	node.prev = r.cur
+	node.next = r.cur.next
+	r.cur.next.prev = node
+	r.cur.next = node
+	r.size++
+}
+
+// InsertBefore links node into the ring just before the cursor. If the
+// ring is empty, node becomes the current node. It panics if node is
+// already in a list or ring.
+func (r *Ring[T]) InsertBefore(node *Node[T]) {
+	r.link(node)
+	if r.cur == nil {
+		r.init(node)
+		return
+	}
+	node.next = r.cur
+	node.prev = r.cur.prev
+	r.cur.prev.next = node
+	r.cur.prev = node
+	r.size++
+}
+
+// link marks node as belonging to the ring. It panics if node is already
+// in a list or ring.
+func (r *Ring[T]) link(node *Node[T]) {
+	if node.linked() {
+		panic("node is already in a list or ring")
+	}
+	if r.token == nil {
+		r.token = new(listToken)
+	}
+	node.list = r.token
+}
+
+func (r *Ring[T]) init(node *Node[T]) {
+	node.next = node
+	node.prev = node
+	r.cur = node
+	r.size = 1
+}
+
+// Remove unlinks the current node and returns it, moving the cursor to the
+// following node. It returns nil if the ring is empty.
+func (r *Ring[T]) Remove() *Node[T] {
+	node := r.cur
+	if node == nil {
+		return nil
+	}
+	if r.size == 1 {
+		r.cur = nil
+	} else {
+		node.prev.next = node.next
+		node.next.prev = node.prev
+		r.cur = node.next
+	}
+	node.prev = nil
+	node.next = nil
+	node.list = nil
+	r.size--
+	return node
+}
+
+// Do calls f on each element of the ring once, starting at the cursor and
+// moving forward. f must not modify the ring.
+func (r *Ring[T]) Do(f func(T)) {
+	if r.cur == nil {
+		return
+	}
+	node := r.cur
+	for i := 0; i < r.size; i++ {
+		f(node.element)
+		node = node.next
+	}
+}
+
+// CheckInvariants verifies that the ring is closed, every next link is
+// mirrored by a prev link, every node belongs to this ring, and the
+// recorded length matches the number of nodes. It is meant for tests.
+func (r *Ring[T]) CheckInvariants() error {
+	if r.cur == nil {
+		if r.size != 0 {
+			return fmt.Errorf("Ring: empty but length is %d", r.size)
+		}
+		return nil
+	}
+	node := r.cur
+	for i := 0; i < r.size; i++ {
+		if node.next == nil || node.next.prev != node {
+			return fmt.Errorf("Ring: node %d next link is not mirrored", i)
+		}
+		if node.list == nil || node.list != r.token {
+			return fmt.Errorf("Ring: node %d belongs to another list or ring", i)
+		}
+		node = node.next
+		if node == r.cur && i != r.size-1 {
+			return fmt.Errorf("Ring: length is %d but only %d nodes are linked", r.size, i+1)
+		}
+	}
+	if node != r.cur {
+		return fmt.Errorf("Ring: more than length %d nodes are linked", r.size)
+	}
+	return nil
+}
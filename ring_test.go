@@ -0,0 +1,217 @@
+package gocontainers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newIntRing(values ...int) *Ring[int] {
+	r := NewRing[int]()
+	for _, v := range values {
+		r.InsertBefore(NewNode(v))
+	}
+	return r
+}
+
+func ringValues[T any](r *Ring[T]) []T {
+	values := []T{}
+	r.Do(func(v T) { values = append(values, v) })
+	return values
+}
+
+func TestRingEmpty(t *testing.T) {
+	r := NewRing[int]()
+	assert.True(t, r.IsEmpty())
+	assert.Equal(t, 0, r.Len())
+	assert.Nil(t, r.Current())
+	assert.Nil(t, r.Advance(3))
+	assert.Nil(t, r.Remove())
+	assert.Equal(t, []int{}, ringValues(r))
+}
+
+func TestRingSingle(t *testing.T) {
+	r := newIntRing(1)
+	node := r.Current()
+	assert.Same(t, node, node.Next())
+	assert.Same(t, node, node.Prev())
+	assert.Same(t, node, r.Advance(5))
+	assert.Same(t, node, r.Advance(-5))
+
+	assert.Same(t, node, r.Remove())
+	assert.Nil(t, node.Next())
+	assert.True(t, r.IsEmpty())
+}
+
+func TestRingInsertBeforeBuildsInOrder(t *testing.T) {
+	r := newIntRing(1, 2, 3)
+	assert.Equal(t, 3, r.Len())
+	assert.Equal(t, 1, r.Current().Get())
+	assert.Equal(t, []int{1, 2, 3}, ringValues(r))
+
+	// tail links back to head
+	assert.Same(t, r.Current(), r.Current().Prev().Next())
+	assert.Equal(t, 3, r.Current().Prev().Get())
+}
+
+func TestRingInsertAfter(t *testing.T) {
+	r := newIntRing(1, 3)
+	r.InsertAfter(NewNode(2))
+	assert.Equal(t, []int{1, 2, 3}, ringValues(r))
+	assert.Equal(t, 1, r.Current().Get())
+}
+
+func TestRingAdvance(t *testing.T) {
+	r := newIntRing(0, 1, 2, 3, 4)
+
+	assert.Equal(t, 1, r.Advance(1).Get())
+	assert.Equal(t, 4, r.Advance(3).Get())
+	assert.Equal(t, 0, r.Advance(1).Get()) // wraps around
+	assert.Equal(t, 3, r.Advance(-2).Get())
+	assert.Equal(t, 3, r.Advance(10).Get())
+	assert.Equal(t, 2, r.Advance(-11).Get())
+	assert.Equal(t, []int{2, 3, 4, 0, 1}, ringValues(r))
+}
+
+func TestRingRoundRobin(t *testing.T) {
+	r := newIntRing(1, 2, 3)
+	var order []int
+	for i := 0; i < 7; i++ {
+		order = append(order, r.Current().Get())
+		r.Advance(1)
+	}
+	assert.Equal(t, []int{1, 2, 3, 1, 2, 3, 1}, order)
+}
+
+func TestRingRemove(t *testing.T) {
+	r := newIntRing(1, 2, 3)
+	r.Advance(2)
+
+	removed := r.Remove()
+	assert.Equal(t, 3, removed.Get())
+	assert.Nil(t, removed.Next())
+	assert.Nil(t, removed.Prev())
+	assert.Equal(t, 2, r.Len())
+	assert.Equal(t, 1, r.Current().Get()) // cursor moved forward, wrapping
+	assert.Equal(t, []int{1, 2}, ringValues(r))
+	assert.Same(t, r.Current(), r.Current().Next().Next())
+
+	r.Remove()
+	r.Remove()
+	assert.True(t, r.IsEmpty())
+	assert.Equal(t, 0, r.Len())
+}
+
+func TestRingClear(t *testing.T) {
+	r := newIntRing(1, 2, 3)
+	r.Clear()
+	assert.True(t, r.IsEmpty())
+	assert.Equal(t, 0, r.Len())
+}
+
+func TestRingRejectsLinkedNodes(t *testing.T) {
+	dll := NewDLL[int]()
+	n := NewNode(1)
+	dll.AddBack(n)
+	r := NewRing[int]()
+	assert.Panics(t, func() { r.InsertAfter(n) })
+	assert.Panics(t, func() { r.InsertBefore(n) })
+	assert.NoError(t, dll.CheckInvariants())
+	assert.True(t, r.IsEmpty())
+
+	dll.DeleteNode(n)
+	r.InsertAfter(n)
+	assert.Panics(t, func() { dll.AddBack(n) })
+	assert.Panics(t, func() { NewRing[int]().InsertBefore(n) })
+	assert.NoError(t, r.CheckInvariants())
+
+	// nodes leave the ring through Remove or Clear
+	m := NewNode(2)
+	r.InsertAfter(m)
+	dll.AddBack(r.Remove())
+	r.Clear()
+	dll.AddBack(m)
+	assert.Equal(t, []int{1, 2}, dll.ToSlice())
+	assert.NoError(t, dll.CheckInvariants())
+}
+
+// FuzzRing applies a random sequence of operations, one per input byte, to
+// a Ring and a slice model (rotated so the cursor is at index 0) and checks
+
+// that they agree after every step.
+func FuzzRing(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3, 4})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		r := NewRing[int]()
+		var model []int
+		for i, op := range ops {
+			v := int(op >> 3)
+			switch op % 5 {
+			case 0:
+				r.InsertAfter(NewNode(v))
+				if len(model) == 0 {
+					model = []int{v}
+				} else {
+					model = slices.Insert(model, 1, v)
+				}
+			case 1:
+				r.InsertBefore(NewNode(v))
+				model = append(model, v)
+			case 2:
+				r.Remove()
+				if len(model) > 0 {
+					model = model[1:]
+				}
+			case 3:
+				n := v - 16
+				r.Advance(n)
+				if len(model) > 0 {
+					k := ((n % len(model)) + len(model)) % len(model)
+					model = append(model[k:], model[:k]...)
+				}
+			case 4:
+				if v%4 == 0 {
+					r.Clear()
+					model = nil
+				}
+			}
+
+			if err := r.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), r.Len())
+			assert.Equal(t, append([]int{}, model...), ringValues(r), "after op %d (%d)", i, op)
+		}
+	})
+}
+
+func benchRingInsertRemove[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		r := NewRing[T]()
+		for _, v := range values {
+			r.InsertBefore(NewNode(v))
+		}
+		for !r.IsEmpty() {
+			r.Remove()
+		}
+	}
+}
+
+func benchRingIterate[T any](b *testing.B, values []T) {
+	r := NewRing[T]()
+	for _, v := range values {
+		r.InsertBefore(NewNode(v))
+	}
+	for b.Loop() {
+		r.Do(func(T) {})
+	}
+}
+
+func BenchmarkRingInsertRemove(b *testing.B) {
+	runBench(b, benchRingInsertRemove[int], benchRingInsertRemove[string])
+}
+
+func BenchmarkRingIterate(b *testing.B) {
+	runBench(b, benchRingIterate[int], benchRingIterate[string])
+}
@@ -0,0 +1,425 @@
+package gocontainers
+
+import (
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+const (
+	// arrayMaxSize is the largest cardinality kept in an arrayContainer; a
+	// bitmapContainer takes 8 KiB, the size of an array of this many values.
+	arrayMaxSize = 4096
+	bitmapWords  = 1 << 16 / 64
+)
+
+// roaringContainer holds the low 16 bits of the values of a RoaringSet that
+// share the same high 16 bits. Mutating methods return the container to use
+// afterwards, which is a different representation when the cardinality
+// crosses arrayMaxSize.
+type roaringContainer interface {
+	cardinality() int
+	contains(x uint16) bool
+	add(x uint16) roaringContainer
+	remove(x uint16) roaringContainer
+	// rank returns the number of values <= x.
+	rank(x uint16) int
+	// selectAt returns the i-th smallest value; i must be below cardinality.
+	selectAt(i int) uint16
+	// all calls yield for each value in ascending order and reports whether
+	// it ran to completion.
+	all(yield func(uint16) bool) bool
+	clone() roaringContainer
+	// bitmap returns the values as a bitmapContainer. It may return the
+	// receiver, so the result must not be modified.
+	bitmap() *bitmapContainer
+}
+
+// arrayContainer is a sorted slice of at most arrayMaxSize values.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (a *arrayContainer) cardinality() int {
+	return len(a.values)
+}
+
+func (a *arrayContainer) contains(x uint16) bool {
+	_, found := slices.BinarySearch(a.values, x)
+	return found
+}
+
+func (a *arrayContainer) add(x uint16) roaringContainer {
+	i, found := slices.BinarySearch(a.values, x)
+	if found {
+		return a
+	}
+	if len(a.values) == arrayMaxSize {
+		return a.bitmap().add(x)
+	}
+	a.values = slices.Insert(a.values, i, x)
+	return a
+}
+
+func (a *arrayContainer) remove(x uint16) roaringContainer {
+	if i, found := slices.BinarySearch(a.values, x); found {
+		a.values = slices.Delete(a.values, i, i+1)
+	}
+	return a
+}
+
+func (a *arrayContainer) rank(x uint16) int {
+	i, found := slices.BinarySearch(a.values, x)
+	if found {
+		return i + 1
+	}
+	return i
+}
+
+func (a *arrayContainer) selectAt(i int) uint16 {
+	return a.values[i]
+}
+
+func (a *arrayContainer) all(yield func(uint16) bool) bool {
+	for _, v := range a.values {
+		if !yield(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *arrayContainer) clone() roaringContainer {
+	return &arrayContainer{values: slices.Clone(a.values)}
+}
+
+func (a *arrayContainer) bitmap() *bitmapContainer {
+	b := &bitmapContainer{card: len(a.values)}
+	for _, v := range a.values {
+		b.words[v/64] |= 1 << (v % 64)
+	}
+	return b
+}
+
+// filter returns the values of a for which other.contains equals keep.
+func (a *arrayContainer) filter(other roaringContainer, keep bool) *arrayContainer {
+	values := make([]uint16, 0, len(a.values))
+	for _, v := range a.values {
+		if other.contains(v) == keep {
+			values = append(values, v)
+		}
+	}
+	return &arrayContainer{values: values}
+}
+
+// bitmapContainer is a bit per possible value, used above arrayMaxSize.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x/64]&(1<<(x%64)) != 0
+}
+
+func (b *bitmapContainer) add(x uint16) roaringContainer {
+	if !b.contains(x) {
+		b.words[x/64] |= 1 << (x % 64)
+		b.card++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) roaringContainer {
+	if !b.contains(x) {
+		return b
+	}
+	b.words[x/64] &^= 1 << (x % 64)
+	b.card--
+	if b.card <= arrayMaxSize {
+		return b.array()
+	}
+	return b
+}
+
+func (b *bitmapContainer) rank(x uint16) int {
+	n := 0
+	for _, word := range b.words[:x/64] {
+		n += bits.OnesCount64(word)
+	}
+	// 2<<63 overflows to 0, so the mask is all ones for the last bit
+	mask := uint64(2)<<(x%64) - 1
+	return n + bits.OnesCount64(b.words[x/64]&mask)
+}
+
+func (b *bitmapContainer) selectAt(i int) uint16 {
+	for w, word := range b.words {
+		if n := bits.OnesCount64(word); i >= n {
+			i -= n
+			continue
+		}
+		for ; i > 0; i-- {
+			word &= word - 1
+		}
+		return uint16(w*64 + bits.TrailingZeros64(word))
+	}
+	panic("selectAt beyond cardinality")
+}
+
+func (b *bitmapContainer) all(yield func(uint16) bool) bool {
+	for w, word := range b.words {
+		for word != 0 {
+			if !yield(uint16(w*64 + bits.TrailingZeros64(word))) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) clone() roaringContainer {
+	c := *b
+	return &c
+}
+
+func (b *bitmapContainer) bitmap() *bitmapContainer {
+	return b
+}
+
+func (b *bitmapContainer) array() *arrayContainer {
+	values := make([]uint16, 0, b.card)
+	b.all(func(v uint16) bool {
+		values = append(values, v)
+		return true
+	})
+	return &arrayContainer{values: values}
+}
+
+// shrink returns b as an arrayContainer if it is small enough to be one.
+func (b *bitmapContainer) shrink() roaringContainer {
+	if b.card <= arrayMaxSize {
+		return b.array()
+	}
+	return b
+}
+
+// interval16 is the inclusive range of values [start, last].
+type interval16 struct {
+	start, last uint16
+}
+
+// runContainer is a sorted list of disjoint ranges. It is
+// produced by RunOptimize and by UnmarshalBinary; adding or removing a value
+// converts it back to an array or bitmap.
+type runContainer struct {
+	runs []interval16
+}
+
+func (r *runContainer) cardinality() int {
+	n := 0
+	for _, run := range r.runs {
+		n += int(run.last-run.start) + 1
+	}
+	return n
+}
+
+func (r *runContainer) contains(x uint16) bool {
+	i := sort.Search(len(r.runs), func(i int) bool { return r.runs[i].last >= x })
+	return i < len(r.runs) && r.runs[i].start <= x
+}
+
+func (r *runContainer) add(x uint16) roaringContainer {
+	if r.contains(x) {
+		return r
+	}
+	return r.expand().add(x)
+}
+
+func (r *runContainer) remove(x uint16) roaringContainer {
+	if !r.contains(x) {
+		return r
+	}
+	return r.expand().remove(x)
+}
+
+func (r *runContainer) rank(x uint16) int {
+	n := 0
+	for _, run := range r.runs {
+		if x < run.start {
+			break
+		}
+		if x <= run.last {
+			return n + int(x-run.start) + 1
+		}
+		n += int(run.last-run.start) + 1
+	}
+	return n
+}
+
+func (r *runContainer) selectAt(i int) uint16 {
+	for _, run := range r.runs {
+		if size := int(run.last-run.start) + 1; i >= size {
+			i -= size
+			continue
+		}
+		return run.start + uint16(i)
+	}
+	panic("selectAt beyond cardinality")
+}
+
+func (r *runContainer) all(yield func(uint16) bool) bool {
+	for _, run := range r.runs {
+		for v := run.start; ; v++ {
+			if !yield(v) {
+				return false
+			}
+			if v == run.last {
+				break
+			}
+		}
+	}
+	return true
+}
+
+func (r *runContainer) clone() roaringContainer {
+	return &runContainer{runs: slices.Clone(r.runs)}
+}
+
+func (r *runContainer) bitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	r.all(func(v uint16) bool {
+		b.words[v/64] |= 1 << (v % 64)
+		return true
+	})
+	b.card = r.cardinality()
+	return b
+}
+
+// expand converts r to an array or bitmap container.
+func (r *runContainer) expand() roaringContainer {
+	return r.bitmap().shrink()
+}
+
+// toRuns returns the values of c as ranges.
+func toRuns(c roaringContainer) []interval16 {
+	var runs []interval16
+	c.all(func(v uint16) bool {
+		if n := len(runs); n > 0 && runs[n-1].last+1 == v {
+			runs[n-1].last = v
+		} else {
+			runs = append(runs, interval16{start: v, last: v})
+		}
+		return true
+	})
+	return runs
+}
+
+// serializedSize returns the number of bytes c takes in the Roaring format.
+func serializedSize(c roaringContainer) int {
+	if r, ok := c.(*runContainer); ok {
+		return 2 + 4*len(r.runs)
+	}
+	return plainSize(c.cardinality())
+}
+
+// plainSize returns the serialized size of an array or bitmap container.
+func plainSize(card int) int {
+	if card <= arrayMaxSize {
+		return 2 * card
+	}
+	return 2 * bitmapWords * 4
+}
+
+// runOptimize returns c as a runContainer if that is smaller, and as an
+// array or bitmap otherwise.
+func runOptimize(c roaringContainer) roaringContainer {
+	runs := toRuns(c)
+	if 2+4*len(runs) < plainSize(c.cardinality()) {
+		return &runContainer{runs: runs}
+	}
+	if r, ok := c.(*runContainer); ok {
+		return r.expand()
+	}
+	return c
+}
+
+func containerAnd(a, b roaringContainer) roaringContainer {
+	if x, ok := a.(*arrayContainer); ok {
+		return x.filter(b, true)
+	}
+	if y, ok := b.(*arrayContainer); ok {
+		return y.filter(a, true)
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+func containerAndNot(a, b roaringContainer) roaringContainer {
+	if x, ok := a.(*arrayContainer); ok {
+		return x.filter(b, false)
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+func containerOr(a, b roaringContainer) roaringContainer {
+	x, xok := a.(*arrayContainer)
+	y, yok := b.(*arrayContainer)
+	if xok && yok {
+		return mergeArrays(x.values, y.values, true)
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+func containerXor(a, b roaringContainer) roaringContainer {
+	x, xok := a.(*arrayContainer)
+	y, yok := b.(*arrayContainer)
+	if xok && yok {
+		return mergeArrays(x.values, y.values, false)
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// mergeArrays merges two sorted value lists, keeping values present in both
+// only if union is set.
+func mergeArrays(a, b []uint16, union bool) roaringContainer {
+	values := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			values = append(values, a[i])
+			i++
+		case a[i] > b[j]:
+			values = append(values, b[j])
+			j++
+		default:
+			if union {
+				values = append(values, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	values = append(values, a[i:]...)
+	values = append(values, b[j:]...)
+
+	result := &arrayContainer{values: values}
+	if len(values) > arrayMaxSize {
+		return result.bitmap()
+	}
+	return result
+}
+
+func bitmapOp(a, b roaringContainer, op func(x, y uint64) uint64) roaringContainer {
+	x, y := a.bitmap(), b.bitmap()
+	result := &bitmapContainer{}
+	for w := range result.words {
+		result.words[w] = op(x.words[w], y.words[w])
+		result.card += bits.OnesCount64(result.words[w])
+	}
+	return result.shrink()
+}
@@ -0,0 +1,108 @@
+package gocontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// containerKinds returns the same values in each container representation.
+func containerKinds(values []uint16) map[string]roaringContainer {
+	array := &arrayContainer{values: values}
+	return map[string]roaringContainer{
+		"array":  array,
+		"bitmap": array.bitmap(),
+		"run":    &runContainer{runs: toRuns(array)},
+	}
+}
+
+func containerValues(c roaringContainer) []uint16 {
+	values := []uint16{}
+	c.all(func(v uint16) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+func TestRoaringContainerQueries(t *testing.T) {
+	values := []uint16{0, 1, 2, 3, 63, 64, 100, 101, 65535}
+	for name, c := range containerKinds(values) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, len(values), c.cardinality())
+			assert.Equal(t, values, containerValues(c))
+			assert.True(t, c.contains(64))
+			assert.False(t, c.contains(65))
+			assert.Equal(t, 1, c.rank(0))
+			assert.Equal(t, 6, c.rank(99))
+			assert.Equal(t, 7, c.rank(100))
+			assert.Equal(t, len(values), c.rank(65535))
+			for i, v := range values {
+				assert.Equal(t, v, c.selectAt(i))
+			}
+		})
+	}
+}
+
+func TestRoaringContainerMutation(t *testing.T) {
+	for name, c := range containerKinds([]uint16{10, 11, 12}) {
+		t.Run(name, func(t *testing.T) {
+			c = c.add(5).add(11).remove(12).remove(500)
+			assert.Equal(t, []uint16{5, 10, 11}, containerValues(c))
+		})
+	}
+}
+
+func TestRoaringContainerConversion(t *testing.T) {
+	var c roaringContainer = &arrayContainer{}
+	for i := range arrayMaxSize {
+		c = c.add(uint16(2 * i))
+	}
+	assert.IsType(t, &arrayContainer{}, c)
+
+	c = c.add(1)
+	assert.IsType(t, &bitmapContainer{}, c)
+	assert.Equal(t, arrayMaxSize+1, c.cardinality())
+
+	c = c.remove(1)
+	assert.IsType(t, &arrayContainer{}, c)
+	assert.Equal(t, arrayMaxSize, c.cardinality())
+}
+
+func TestRoaringContainerRunOptimize(t *testing.T) {
+	dense := make([]uint16, 1000)
+	for i := range dense {
+		dense[i] = uint16(i)
+	}
+	c := runOptimize(&arrayContainer{values: dense})
+	assert.Equal(t, []interval16{{0, 999}}, c.(*runContainer).runs)
+
+	sparse := []uint16{1, 3, 5}
+	c = runOptimize(&runContainer{runs: toRuns(&arrayContainer{values: sparse})})
+	assert.IsType(t, &arrayContainer{}, c)
+	assert.Equal(t, sparse, containerValues(c))
+}
+
+func TestRoaringContainerOps(t *testing.T) {
+	a := []uint16{1, 2, 3, 100, 200}
+	b := []uint16{2, 3, 4, 200, 300}
+	ops := []struct {
+		name string
+		op   func(a, b roaringContainer) roaringContainer
+		want []uint16
+	}{
+		{"and", containerAnd, []uint16{2, 3, 200}},
+		{"or", containerOr, []uint16{1, 2, 3, 4, 100, 200, 300}},
+		{"andNot", containerAndNot, []uint16{1, 100}},
+		{"xor", containerXor, []uint16{1, 4, 100, 300}},
+	}
+	for _, op := range ops {
+		for aName, x := range containerKinds(a) {
+			for bName, y := range containerKinds(b) {
+				t.Run(op.name+"/"+aName+"/"+bName, func(t *testing.T) {
+					assert.Equal(t, op.want, containerValues(op.op(x, y)))
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package gocontainers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// RoaringSet is a compressed set of uint32 values. Values are grouped by
+// their high 16 bits into chunks, and each chunk is stored as a sorted
+// array, a bitmap or a list of runs, whichever suits its contents. This
+// keeps both sparse and dense sets small while set operations work on whole
+// chunks at a time.
+//
+// Chunks become run-encoded only through RunOptimize or UnmarshalBinary.
+// MarshalBinary writes the portable Roaring format, so sets can be
+// exchanged with the Roaring libraries for other languages.
+type RoaringSet struct {
+	keys       []uint16
+	containers []roaringContainer
+}
+
+const (
+	roaringCookieNoRuns = 12346
+	roaringCookie       = 12347
+	// roaringNoOffsetThreshold is the container count below which the
+	// offset header is omitted from data that has run containers.
+	roaringNoOffsetThreshold = 4
+)
+
+func NewRoaringSet() *RoaringSet {
+	return &RoaringSet{}
+}
+
+func splitValue(x uint32) (hi, lo uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+// Add adds x to the set.
+func (s *RoaringSet) Add(x uint32) {
+	hi, lo := splitValue(x)
+	i, found := slices.BinarySearch(s.keys, hi)
+	if found {
+		s.containers[i] = s.containers[i].add(lo)
+		return
+	}
+	s.keys = slices.Insert(s.keys, i, hi)
+	s.containers = slices.Insert(s.containers, i, roaringContainer(&arrayContainer{values: []uint16{lo}}))
+}
+
+// Remove removes x from the set.
+func (s *RoaringSet) Remove(x uint32) {
+	hi, lo := splitValue(x)
+	i, found := slices.BinarySearch(s.keys, hi)
+	if !found {
+		return
+	}
+	c := s.containers[i].remove(lo)
+	if c.cardinality() == 0 {
+		s.keys = slices.Delete(s.keys, i, i+1)
+		s.containers = slices.Delete(s.containers, i, i+1)
+		return
+	}
+	s.containers[i] = c
+}
+
+// Contains reports whether x is in the set.
+func (s *RoaringSet) Contains(x uint32) bool {
+	hi, lo := splitValue(x)
+	i, found := slices.BinarySearch(s.keys, hi)
+	return found && s.containers[i].contains(lo)
+}
+
+// Cardinality returns the number of values in the set.
+func (s *RoaringSet) Cardinality() uint64 {
+	var n uint64
+	for _, c := range s.containers {
+		n += uint64(c.cardinality())
+	}
+	return n
+}
+
+// Size returns the number of values in the set. It is the same as
+// Cardinality.
+func (s *RoaringSet) Size() int {
+	return int(s.Cardinality())
+}
+
+func (s *RoaringSet) IsEmpty() bool {
+	return len(s.keys) == 0
+}
+
+func (s *RoaringSet) Clear() {
+	s.keys = nil
+	s.containers = nil
+}
+
+// Rank returns the number of values in the set that are <= x.
+func (s *RoaringSet) Rank(x uint32) uint64 {
+	hi, lo := splitValue(x)
+	var n uint64
+	for i, key := range s.keys {
+		if key > hi {
+			break
+		}
+		if key == hi {
+			return n + uint64(s.containers[i].rank(lo))
+		}
+		n += uint64(s.containers[i].cardinality())
+	}
+	return n
+}
+
+// Select returns the i-th smallest value in the set, counting from 0. The
+// boolean is false if the set has i or fewer values.
+func (s *RoaringSet) Select(i uint64) (uint32, bool) {
+	for k, c := range s.containers {
+		if card := uint64(c.cardinality()); i >= card {
+			i -= card
+			continue
+		}
+		return uint32(s.keys[k])<<16 | uint32(c.selectAt(int(i))), true
+	}
+	return 0, false
+}
+
+// All returns an iterator over the values in ascending order.
+func (s *RoaringSet) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range s.containers {
+			hi := uint32(s.keys[i]) << 16
+			if !c.all(func(lo uint16) bool { return yield(hi | uint32(lo)) }) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice returns the values in ascending order.
+func (s *RoaringSet) ToSlice() []uint32 {
+	result := make([]uint32, 0, s.Cardinality())
+	for x := range s.All() {
+		result = append(result, x)
+	}
+	return result
+}
+
+// Clone returns an independent copy of the set.
+func (s *RoaringSet) Clone() *RoaringSet {
+	result := &RoaringSet{
+		keys:       slices.Clone(s.keys),
+		containers: make([]roaringContainer, len(s.containers)),
+	}
+	for i, c := range s.containers {
+		result.containers[i] = c.clone()
+	}
+	return result
+}
+
+// Equal reports whether both sets contain the same values.
+func (s *RoaringSet) Equal(other *RoaringSet) bool {
+	if !slices.Equal(s.keys, other.keys) {
+		return false
+	}
+	for i, c := range s.containers {
+		card := c.cardinality()
+		if other.containers[i].cardinality() != card || containerAnd(c, other.containers[i]).cardinality() != card {
+			return false
+		}
+	}
+	return true
+}
+
+// And returns a new set containing the values in both sets.
+func (s *RoaringSet) And(other *RoaringSet) *RoaringSet {
+	return s.combine(other, containerAnd, false, false)
+}
+
+// Or returns a new set containing the values in either set.
+func (s *RoaringSet) Or(other *RoaringSet) *RoaringSet {
+	return s.combine(other, containerOr, true, true)
+}
+
+// AndNot returns a new set containing the values in s that are not in other.
+func (s *RoaringSet) AndNot(other *RoaringSet) *RoaringSet {
+	return s.combine(other, containerAndNot, true, false)
+}
+
+// Xor returns a new set containing the values in exactly one of the sets.
+func (s *RoaringSet) Xor(other *RoaringSet) *RoaringSet {
+	return s.combine(other, containerXor, true, true)
+}
+
+// combine walks the chunks of both sets in key order. Chunks present in both
+// are merged with op; chunks present in only one are copied if keepLeft or
+// keepRight is set for that side.
+func (s *RoaringSet) combine(other *RoaringSet, op func(a, b roaringContainer) roaringContainer, keepLeft, keepRight bool) *RoaringSet {
+	result := NewRoaringSet()
+	appendContainer := func(key uint16, c roaringContainer) {
+		if c.cardinality() > 0 {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(s.keys) && s.keys[i] < other.keys[j]):
+			if keepLeft {
+				appendContainer(s.keys[i], s.containers[i].clone())
+			}
+			i++
+		case i == len(s.keys) || s.keys[i] > other.keys[j]:
+			if keepRight {
+				appendContainer(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			appendContainer(s.keys[i], op(s.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// RunOptimize run-length encodes the chunks where that takes less space
+// than an array or bitmap, and expands run-encoded chunks where it does not.
+func (s *RoaringSet) RunOptimize() {
+	for i, c := range s.containers {
+		s.containers[i] = runOptimize(c)
+	}
+}
+
+// MarshalBinary encodes the set in the portable Roaring serialization
+// format, as described at https://github.com/RoaringBitmap/RoaringFormatSpec.
+func (s *RoaringSet) MarshalBinary() ([]byte, error) {
+	n := len(s.keys)
+	hasRuns := false
+	size := 0
+	for _, c := range s.containers {
+		if _, ok := c.(*runContainer); ok {
+			hasRuns = true
+		}
+		size += 4 + 4 + serializedSize(c)
+	}
+
+	data := make([]byte, 0, 8+(n+7)/8+size)
+	if hasRuns {
+		data = binary.LittleEndian.AppendUint32(data, roaringCookie|uint32(n-1)<<16)
+		runFlags := make([]byte, (n+7)/8)
+		for i, c := range s.containers {
+			if _, ok := c.(*runContainer); ok {
+				runFlags[i/8] |= 1 << (i % 8)
+			}
+		}
+		data = append(data, runFlags...)
+	} else {
+		data = binary.LittleEndian.AppendUint32(data, roaringCookieNoRuns)
+		data = binary.LittleEndian.AppendUint32(data, uint32(n))
+	}
+
+	for i, c := range s.containers {
+		data = binary.LittleEndian.AppendUint16(data, s.keys[i])
+		data = binary.LittleEndian.AppendUint16(data, uint16(c.cardinality()-1))
+	}
+
+	if !hasRuns || n >= roaringNoOffsetThreshold {
+		offset := len(data) + 4*n
+		for _, c := range s.containers {
+			data = binary.LittleEndian.AppendUint32(data, uint32(offset))
+			offset += serializedSize(c)
+		}
+	}
+
+	for _, c := range s.containers {
+		data = appendRoaringContainer(data, c)
+	}
+	return data, nil
+}
+
+func appendRoaringContainer(data []byte, c roaringContainer) []byte {
+	if r, ok := c.(*runContainer); ok {
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(r.runs)))
+		for _, run := range r.runs {
+			data = binary.LittleEndian.AppendUint16(data, run.start)
+			data = binary.LittleEndian.AppendUint16(data, run.last-run.start)
+		}
+		return data
+	}
+	if c.cardinality() <= arrayMaxSize {
+		c.all(func(v uint16) bool {
+			data = binary.LittleEndian.AppendUint16(data, v)
+			return true
+		})
+		return data
+	}
+	for _, word := range c.bitmap().words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data
+}
+
+// UnmarshalBinary replaces the contents of the set with data in the
+// portable Roaring serialization format, as written by MarshalBinary or by
+// another Roaring implementation.
+func (s *RoaringSet) UnmarshalBinary(data []byte) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("RoaringSet: %w: %s", ErrInvalidEncoding, fmt.Sprintf(format, args...))
+	}
+
+	if len(data) < 4 {
+		return invalid("missing cookie")
+	}
+	cookie := binary.LittleEndian.Uint32(data)
+	pos := 4
+	var n int
+	var runFlags []byte
+	switch {
+	case cookie == roaringCookieNoRuns:
+		if len(data) < 8 {
+			return invalid("missing container count")
+		}
+		n = int(binary.LittleEndian.Uint32(data[4:]))
+		pos = 8
+	case cookie&0xFFFF == roaringCookie:
+		n = int(cookie>>16) + 1
+		flagBytes := (n + 7) / 8
+		if len(data)-pos < flagBytes {
+			return invalid("truncated run flags")
+		}
+		runFlags = data[pos : pos+flagBytes]
+		pos += flagBytes
+	default:
+		return invalid("unknown cookie %d", cookie)
+	}
+	if n > 1<<16 {
+		return invalid("%d containers", n)
+	}
+
+	if len(data)-pos < 4*n {
+		return invalid("truncated header")
+	}
+	keys := make([]uint16, n)
+	cards := make([]int, n)
+	for i := range n {
+		keys[i] = binary.LittleEndian.Uint16(data[pos:])
+		cards[i] = int(binary.LittleEndian.Uint16(data[pos+2:])) + 1
+		pos += 4
+		if i > 0 && keys[i] <= keys[i-1] {
+			return invalid("keys out of order")
+		}
+	}
+	if runFlags == nil || n >= roaringNoOffsetThreshold {
+		if len(data)-pos < 4*n {
+			return invalid("truncated offsets")
+		}
+		pos += 4 * n
+	}
+
+	containers := make([]roaringContainer, n)
+	for i := range n {
+		var c roaringContainer
+		var err error
+		switch {
+		case runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0:
+			c, pos, err = readRunContainer(data, pos)
+		case cards[i] <= arrayMaxSize:
+			c, pos, err = readArrayContainer(data, pos, cards[i])
+		default:
+			c, pos, err = readBitmapContainer(data, pos)
+		}
+		if err != nil {
+			return invalid("container %d: %v", i, err)
+		}
+		if c.cardinality() != cards[i] {
+			return invalid("container %d has %d values, header says %d", i, c.cardinality(), cards[i])
+		}
+		containers[i] = c
+	}
+
+	s.keys = keys
+	s.containers = containers
+	return nil
+}
+
+func readArrayContainer(data []byte, pos, card int) (roaringContainer, int, error) {
+	if len(data)-pos < 2*card {
+		return nil, pos, fmt.Errorf("truncated array")
+	}
+	values := make([]uint16, card)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(data[pos+2*i:])
+		if i > 0 && values[i] <= values[i-1] {
+			return nil, pos, fmt.Errorf("array values out of order")
+		}
+	}
+	return &arrayContainer{values: values}, pos + 2*card, nil
+}
+
+func readBitmapContainer(data []byte, pos int) (roaringContainer, int, error) {
+	if len(data)-pos < 8*bitmapWords {
+		return nil, pos, fmt.Errorf("truncated bitmap")
+	}
+	b := &bitmapContainer{}
+	for w := range b.words {
+		b.words[w] = binary.LittleEndian.Uint64(data[pos+8*w:])
+	}
+	for _, word := range b.words {
+		b.card += bits.OnesCount64(word)
+	}
+	return b, pos + 8*bitmapWords, nil
+}
+
+func readRunContainer(data []byte, pos int) (roaringContainer, int, error) {
+	if len(data)-pos < 2 {
+		return nil, pos, fmt.Errorf("truncated run count")
+	}
+	count := int(binary.LittleEndian.Uint16(data[pos:]))
+	pos += 2
+	if len(data)-pos < 4*count {
+		return nil, pos, fmt.Errorf("truncated runs")
+	}
+	runs := make([]interval16, count)
+	for i := range runs {
+		start := int(binary.LittleEndian.Uint16(data[pos:]))
+		last := start + int(binary.LittleEndian.Uint16(data[pos+2:]))
+		pos += 4
+		if last > 0xFFFF {
+			return nil, pos, fmt.Errorf("run overflows the chunk")
+		}
+		if i > 0 && start <= int(runs[i-1].last) {
+			return nil, pos, fmt.Errorf("runs overlap or are out of order")
+		}
+		runs[i] = interval16{start: uint16(start), last: uint16(last)}
+	}
+	return &runContainer{runs: runs}, pos, nil
+}
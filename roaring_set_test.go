@@ -0,0 +1,388 @@
+package gocontainers
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRoaringSet(values ...uint32) *RoaringSet {
+	s := NewRoaringSet()
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s
+}
+
+// randomRoaringValues returns sorted distinct values spread over a few
+// chunks with sparse, dense and run-shaped regions, so every container kind
+// takes part.
+func randomRoaringValues(r *rand.Rand) []uint32 {
+	seen := map[uint32]bool{}
+	for range 200 {
+		seen[r.Uint32N(1<<20)] = true
+	}
+	for range 6000 {
+		seen[1<<16|r.Uint32N(1<<16)] = true
+	}
+	start := 3<<16 + r.Uint32N(1000)
+	for i := range uint32(3000) {
+		seen[start+i] = true
+	}
+	values := make([]uint32, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values
+}
+
+func TestRoaringSetAddRemove(t *testing.T) {
+	s := NewRoaringSet()
+	assert.True(t, s.IsEmpty())
+
+	s.Add(5)
+	s.Add(1 << 20)
+	s.Add(5)
+	s.Add(1<<32 - 1)
+	assert.True(t, s.Contains(5))
+	assert.True(t, s.Contains(1<<20))
+	assert.True(t, s.Contains(1<<32-1))
+	assert.False(t, s.Contains(6))
+	assert.Equal(t, uint64(3), s.Cardinality())
+	assert.Equal(t, 3, s.Size())
+	assert.Equal(t, []uint32{5, 1 << 20, 1<<32 - 1}, s.ToSlice())
+
+	s.Remove(1 << 20)
+	s.Remove(7)
+	assert.Equal(t, []uint32{5, 1<<32 - 1}, s.ToSlice())
+	assert.Len(t, s.keys, 2)
+
+	s.Clear()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, uint64(0), s.Cardinality())
+}
+
+func TestRoaringSetDenseChunk(t *testing.T) {
+	s := NewRoaringSet()
+	for i := range uint32(10000) {
+		s.Add(i * 3)
+	}
+	assert.Equal(t, uint64(10000), s.Cardinality())
+	assert.True(t, s.Contains(29997))
+	assert.False(t, s.Contains(29998))
+
+	for i := range uint32(10000) {
+		s.Remove(i * 3)
+	}
+	assert.True(t, s.IsEmpty())
+}
+
+func TestRoaringSetRankSelect(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	values := randomRoaringValues(r)
+	s := newRoaringSet(values...)
+	s.RunOptimize()
+
+	for i, v := range values {
+		require.Equal(t, uint64(i+1), s.Rank(v))
+		got, ok := s.Select(uint64(i))
+		require.True(t, ok)
+		require.Equal(t, v, got)
+	}
+	assert.Equal(t, uint64(0), newRoaringSet(10).Rank(9))
+	assert.Equal(t, uint64(len(values)), s.Rank(1<<32-1))
+	_, ok := s.Select(uint64(len(values)))
+	assert.False(t, ok)
+}
+
+func TestRoaringSetAll(t *testing.T) {
+	s := newRoaringSet(1<<17, 3, 70000)
+	assert.Equal(t, []uint32{3, 70000, 1 << 17}, slices.Collect(s.All()))
+
+	var first []uint32
+	for v := range s.All() {
+		first = append(first, v)
+		break
+	}
+	assert.Equal(t, []uint32{3}, first)
+}
+
+func TestRoaringSetOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for _, optimize := range []bool{false, true} {
+		av, bv := randomRoaringValues(r), randomRoaringValues(r)
+		a, b := newRoaringSet(av...), newRoaringSet(bv...)
+		if optimize {
+			a.RunOptimize()
+			b.RunOptimize()
+		}
+		inA, inB := map[uint32]bool{}, map[uint32]bool{}
+		for _, v := range av {
+			inA[v] = true
+		}
+		for _, v := range bv {
+			inB[v] = true
+		}
+		model := func(keep func(x, y bool) bool) []uint32 {
+			var want []uint32
+			for _, v := range slices.Concat(av, bv) {
+				if keep(inA[v], inB[v]) {
+					want = append(want, v)
+				}
+			}
+			slices.Sort(want)
+			return slices.Compact(want)
+		}
+
+		tests := []struct {
+			name string
+			got  *RoaringSet
+			want []uint32
+		}{
+			{"And", a.And(b), model(func(x, y bool) bool { return x && y })},
+			{"Or", a.Or(b), model(func(x, y bool) bool { return x || y })},
+			{"AndNot", a.AndNot(b), model(func(x, y bool) bool { return x && !y })},
+			{"Xor", a.Xor(b), model(func(x, y bool) bool { return x != y })},
+		}
+		for _, tt := range tests {
+			assert.Equal(t, tt.want, tt.got.ToSlice(), "%s optimize=%v", tt.name, optimize)
+			assert.True(t, tt.got.Equal(newRoaringSet(tt.want...)), "%s optimize=%v", tt.name, optimize)
+		}
+
+		// operands are unchanged
+		assert.Equal(t, av, a.ToSlice())
+		assert.Equal(t, bv, b.ToSlice())
+	}
+}
+
+func TestRoaringSetEqualAndClone(t *testing.T) {
+	a := newRoaringSet(1, 2, 3, 1<<20)
+	c := a.Clone()
+	assert.True(t, a.Equal(c))
+
+	c.RunOptimize()
+	assert.True(t, a.Equal(c))
+
+	c.Add(4)
+	assert.False(t, a.Contains(4))
+	assert.False(t, a.Equal(c))
+	assert.False(t, a.Equal(newRoaringSet(1, 2, 3)))
+}
+
+func TestRoaringSetMarshalNoRuns(t *testing.T) {
+	s := newRoaringSet(1, 2, 3, 1<<16+5)
+	data, err := s.MarshalBinary()
+	require.NoError(t, err)
+
+	want := []byte{
+		0x3a, 0x30, 0, 0, // cookie 12346
+		2, 0, 0, 0, // container count
+		0, 0, 2, 0, // key 0, cardinality 3
+		1, 0, 0, 0, // key 1, cardinality 1
+		24, 0, 0, 0, // offset of container 0
+		30, 0, 0, 0, // offset of container 1
+		1, 0, 2, 0, 3, 0,
+		5, 0,
+	}
+	assert.Equal(t, want, data)
+}
+
+func TestRoaringSetMarshalRuns(t *testing.T) {
+	s := NewRoaringSet()
+	for i := range uint32(10) {
+		s.Add(i + 1)
+	}
+	s.Add(1<<16 + 5)
+	s.RunOptimize()
+	data, err := s.MarshalBinary()
+	require.NoError(t, err)
+
+	want := []byte{
+		0x3b, 0x30, 1, 0, // cookie 12347, container count 2
+		0x01,       // container 0 is a run container
+		0, 0, 9, 0, // key 0, cardinality 10
+		1, 0, 0, 0, // key 1, cardinality 1
+		1, 0, 1, 0, 9, 0, // one run of [1, 10]
+		5, 0,
+	}
+	assert.Equal(t, want, data)
+}
+
+func TestRoaringSetBinaryRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	values := randomRoaringValues(r)
+	for _, optimize := range []bool{false, true} {
+		s := newRoaringSet(values...)
+		if optimize {
+			s.RunOptimize()
+		}
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+
+		var got RoaringSet
+		require.NoError(t, got.UnmarshalBinary(data))
+		assert.Equal(t, values, got.ToSlice())
+		for i, c := range s.containers {
+			assert.IsType(t, c, got.containers[i])
+		}
+	}
+
+	data, err := NewRoaringSet().MarshalBinary()
+	require.NoError(t, err)
+	var empty RoaringSet
+	require.NoError(t, empty.UnmarshalBinary(data))
+	assert.True(t, empty.IsEmpty())
+}
+
+func TestRoaringSetUnmarshalInvalid(t *testing.T) {
+	valid, err := newRoaringSet(1, 2, 3, 1<<16+5).MarshalBinary()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown cookie", []byte{1, 2, 3, 4, 0, 0, 0, 0}},
+		{"truncated header", valid[:10]},
+		{"truncated container", valid[:len(valid)-1]},
+		{"keys out of order", []byte{0x3a, 0x30, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 24, 0, 0, 0, 26, 0, 0, 0, 1, 0, 1, 0}},
+		{"array out of order", []byte{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 16, 0, 0, 0, 2, 0, 1, 0}},
+		{"run overflow", []byte{0x3b, 0x30, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0xff, 0xff, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s RoaringSet
+			err := s.UnmarshalBinary(tt.data)
+			assert.True(t, errors.Is(err, ErrInvalidEncoding), "got %v", err)
+		})
+	}
+}
+
+// FuzzRoaringSet applies a random sequence of operations, three bytes each,
+// to a RoaringSet and a map model and checks that they agree, including
+// after a round trip through MarshalBinary.
+func FuzzRoaringSet(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 0, 1, 3, 1, 1, 2, 2, 0, 0})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := NewRoaringSet()
+		model := map[uint32]bool{}
+		for i := 0; i+2 < len(ops); i += 3 {
+			// a few chunks, and values dense enough to fill them
+			v := uint32(ops[i+1]&3)<<16 | uint32(ops[i+2])<<4
+			switch ops[i] % 4 {
+			case 0:
+				for j := range uint32(ops[i]>>2) + 1 {
+					s.Add(v + j)
+					model[v+j] = true
+				}
+			case 1:
+				s.Remove(v)
+				delete(model, v)
+			case 2:
+				s.RunOptimize()
+			case 3:
+				data, err := s.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				s = NewRoaringSet()
+				if err := s.UnmarshalBinary(data); err != nil {
+					t.Fatalf("op %d: UnmarshalBinary: %v", i/3, err)
+				}
+			}
+
+			want := make([]uint32, 0, len(model))
+			for v := range model {
+				want = append(want, v)
+			}
+			slices.Sort(want)
+			if got := s.ToSlice(); !slices.Equal(got, want) {
+				t.Fatalf("op %d: ToSlice = %v, want %v", i/3, got, want)
+			}
+			if s.Size() != len(model) {
+				t.Fatalf("op %d: Size = %d, want %d", i/3, s.Size(), len(model))
+			}
+		}
+	})
+}
+
+// FuzzRoaringSetUnmarshal checks that UnmarshalBinary rejects malformed
+// input without panicking, and that accepted input survives a round trip.
+func FuzzRoaringSetUnmarshal(f *testing.F) {
+	for _, s := range []*RoaringSet{NewRoaringSet(), newRoaringSet(1, 2, 3, 1<<16+5)} {
+		data, _ := s.MarshalBinary()
+		f.Add(data)
+		s.RunOptimize()
+		data, _ = s.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var s RoaringSet
+		if s.UnmarshalBinary(data) != nil {
+			return
+		}
+		encoded, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again RoaringSet
+		if err := again.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("re-encoded data rejected: %v", err)
+		}
+		if !s.Equal(&again) {
+			t.Fatal("round trip changed the set")
+		}
+	})
+}
+
+// roaringBenchValue spreads benchmark values over several containers.
+func roaringBenchValue(v int) uint32 {
+	return uint32(v) * 37
+}
+
+func BenchmarkRoaringSetAddRemove(b *testing.B) {
+	runBenchSizes(b, func(b *testing.B, values []int) {
+		for b.Loop() {
+			s := NewRoaringSet()
+			for _, v := range values {
+				s.Add(roaringBenchValue(v))
+			}
+			for _, v := range values {
+				s.Remove(roaringBenchValue(v))
+			}
+		}
+	})
+}
+
+func BenchmarkRoaringSetContains(b *testing.B) {
+	runBenchSizes(b, func(b *testing.B, values []int) {
+		s := NewRoaringSet()
+		for _, v := range values {
+			s.Add(roaringBenchValue(v))
+		}
+		for b.Loop() {
+			for _, v := range values {
+				s.Contains(roaringBenchValue(v))
+			}
+		}
+	})
+}
+
+func BenchmarkRoaringSetIterate(b *testing.B) {
+	runBenchSizes(b, func(b *testing.B, values []int) {
+		s := NewRoaringSet()
+		for _, v := range values {
+			s.Add(roaringBenchValue(v))
+		}
+		for b.Loop() {
+			for range s.All() {
+			}
+		}
+	})
+}
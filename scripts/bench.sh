@@ -0,0 +1,35 @@
+#!/bin/sh
+# Runs the container benchmark suite and compares it against the checked-in
+# baseline with benchstat.
+#
+#   scripts/bench.sh           compare the current tree with the baseline
+#   scripts/bench.sh -update   rewrite the baseline from the current tree
+#
+# Results are only comparable on the machine that produced the baseline, so
+# refresh it with -update before comparing on a new machine.
+set -eu
+
+cd "$(dirname "$0")/.."
+
+baseline=testdata/bench/baseline.txt
+
+# benchstat is pinned so its output format cannot change under the
+# baseline; set BENCHSTAT to use another build.
+benchstat_version=v0.0.0-20260908200009-22c9c6c9d4da
+benchstat=${BENCHSTAT:-"go run golang.org/x/perf/cmd/benchstat@$benchstat_version"}
+
+run() {
+	go test -run '^$' -bench . \
+		-benchmem -benchtime 100ms -count "${BENCH_COUNT:-5}" .
+}
+
+if [ "${1:-}" = "-update" ]; then
+	run > "$baseline"
+	echo "wrote $baseline"
+	exit 0
+fi
+
+current=$(mktemp)
+trap 'rm -f "$current"' EXIT
+run > "$current"
+$benchstat "$baseline" "$current"
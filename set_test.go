@@ -264,3 +264,86 @@ func TestSetToSlice(t *testing.T) {
 		})
 	}
 }
+
+// FuzzSet applies a random sequence of operations, one per input byte, to a
+// Set and a map model and checks that they agree after every step.
+func FuzzSet(f *testing.F) {
+	f.Add([]byte{0, 3, 6, 1, 2})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := NewSet[int]()
+		model := map[int]bool{}
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 4 {
+			case 0, 1:
+				s.Add(v)
+				model[v] = true
+			case 2:
+				s.Remove(v)
+				delete(model, v)
+			case 3:
+				if v%8 == 0 {
+					s.Clear()
+					clear(model)
+				}
+			}
+
+			if s.Size() != len(model) || s.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Size = %d, want %d", i, s.Size(), len(model))
+			}
+			if s.Contains(v) != model[v] {
+				t.Fatalf("op %d: Contains(%d) = %v, want %v", i, v, s.Contains(v), model[v])
+			}
+			if len(s.ToSlice()) != len(model) {
+				t.Fatalf("op %d: ToSlice has %d elements, want %d", i, len(s.ToSlice()), len(model))
+			}
+		}
+	})
+}
+
+func benchSetAddRemove[T comparable](b *testing.B, values []T) {
+	for b.Loop() {
+		s := NewSet[T]()
+		for _, v := range values {
+			s.Add(v)
+		}
+		for _, v := range values {
+			s.Remove(v)
+		}
+	}
+}
+
+func benchSetContains[T comparable](b *testing.B, values []T) {
+	s := NewSet[T]()
+	for _, v := range values {
+		s.Add(v)
+	}
+	for b.Loop() {
+		for _, v := range values {
+			s.Contains(v)
+		}
+	}
+}
+
+func benchSetIterate[T comparable](b *testing.B, values []T) {
+	s := NewSet[T]()
+	for _, v := range values {
+		s.Add(v)
+	}
+	for b.Loop() {
+		for range s.elements {
+		}
+	}
+}
+
+func BenchmarkSetAddRemove(b *testing.B) {
+	runBench(b, benchSetAddRemove[int], benchSetAddRemove[string])
+}
+
+func BenchmarkSetContains(b *testing.B) {
+	runBench(b, benchSetContains[int], benchSetContains[string])
+}
+
+func BenchmarkSetIterate(b *testing.B) {
+	runBench(b, benchSetIterate[int], benchSetIterate[string])
+}
@@ -1,10 +1,12 @@
 package gocontainers
 
-type Stack[T comparable] struct {
+import "slices"
+
+type Stack[T any] struct {
 	elements []T
 }
 
-func NewStack[T comparable]() *Stack[T] {
+func NewStack[T any]() *Stack[T] {
 	return &Stack[T]{elements: make([]T, 0)}
 }
 
@@ -40,3 +42,27 @@ func (s *Stack[T]) Peek() T {
 	}
 	return s.elements[len(s.elements)-1]
 }
+
+// RemoveBottom removes and returns the oldest element of the stack.
+func (s *Stack[T]) RemoveBottom() T {
+	if len(s.elements) == 0 {
+		panic("RemoveBottom from empty stack")
+	}
+	var zero T
+	bottom := s.elements[0]
+	s.elements[0] = zero
+	s.elements = s.elements[1:]
+	return bottom
+}
+
+// ContainsFunc reports whether at least one element e satisfies f(e).
+func (s *Stack[T]) ContainsFunc(f func(T) bool) bool {
+	return slices.ContainsFunc(s.elements, f)
+}
+
+// ToSlice returns the elements from bottom to top.
+func (s *Stack[T]) ToSlice() []T {
+	result := make([]T, len(s.elements))
+	copy(result, s.elements)
+	return result
+}
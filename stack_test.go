@@ -118,3 +118,156 @@ func TestStack_Peek(t *testing.T) {
 		t.Errorf("Peek after pop should return 1, got %v", s.Peek())
 	}
 }
+
+func TestStack_RemoveBottom(t *testing.T) {
+	s := NewStack[int]()
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+
+	if got := s.RemoveBottom(); got != 1 {
+		t.Errorf("RemoveBottom should return 1, got %v", got)
+	}
+	if s.Size() != 2 {
+		t.Errorf("Size should be 2, got %d", s.Size())
+	}
+	if got := s.Peek(); got != 3 {
+		t.Errorf("Peek should still return 3, got %v", got)
+	}
+	if got := s.RemoveBottom(); got != 2 {
+		t.Errorf("RemoveBottom should return 2, got %v", got)
+	}
+	if got := s.Pop(); got != 3 {
+		t.Errorf("Pop should return 3, got %v", got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("RemoveBottom on empty stack should panic")
+		}
+	}()
+	s.RemoveBottom()
+}
+
+func TestStack_NonComparable(t *testing.T) {
+	s := NewStack[func() int]()
+	s.Push(func() int { return 1 })
+	s.Push(func() int { return 2 })
+
+	if got := s.Pop()(); got != 2 {
+		t.Errorf("Pop should return the func returning 2, got %v", got)
+	}
+	if got := s.Peek()(); got != 1 {
+		t.Errorf("Peek should return the func returning 1, got %v", got)
+	}
+}
+
+func TestStack_ContainsFunc(t *testing.T) {
+	s := NewStack[[]byte]()
+	if s.ContainsFunc(func([]byte) bool { return true }) {
+		t.Error("Empty stack should contain nothing")
+	}
+	s.Push([]byte("first"))
+	s.Push([]byte("second"))
+	if !s.ContainsFunc(func(b []byte) bool { return string(b) == "first" }) {
+		t.Error("Stack should contain first")
+	}
+	s.Pop()
+	if s.ContainsFunc(func(b []byte) bool { return string(b) == "second" }) {
+		t.Error("Stack should not contain second after it was popped")
+	}
+}
+
+// FuzzStack applies a random sequence of operations, one per input byte, to
+// a Stack and a slice model and checks that they agree after every step.
+func FuzzStack(f *testing.F) {
+	f.Add([]byte{0, 4, 1, 2, 3})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		s := NewStack[int]()
+		var model []int
+		for i, op := range ops {
+			v := int(op >> 3)
+			switch op % 5 {
+			case 0, 1:
+				s.Push(v)
+				model = append(model, v)
+			case 2:
+				if len(model) > 0 {
+					want := model[len(model)-1]
+					model = model[:len(model)-1]
+					if got := s.Pop(); got != want {
+						t.Fatalf("op %d: Pop = %d, want %d", i, got, want)
+					}
+				}
+			case 3:
+				if len(model) > 0 {
+					want := model[0]
+					model = model[1:]
+					if got := s.RemoveBottom(); got != want {
+						t.Fatalf("op %d: RemoveBottom = %d, want %d", i, got, want)
+					}
+				}
+			case 4:
+				if v%4 == 0 {
+					s.Clear()
+					model = nil
+				}
+			}
+
+			if s.Size() != len(model) || s.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Size = %d, want %d", i, s.Size(), len(model))
+			}
+			if len(model) > 0 && s.Peek() != model[len(model)-1] {
+				t.Fatalf("op %d: Peek = %d, want %d", i, s.Peek(), model[len(model)-1])
+			}
+		}
+	})
+}
+
+func benchStackPushPop[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		s := NewStack[T]()
+		for _, v := range values {
+			s.Push(v)
+		}
+		for !s.IsEmpty() {
+			s.Pop()
+		}
+	}
+}
+
+func benchStackIterate[T any](b *testing.B, values []T) {
+	s := NewStack[T]()
+	for _, v := range values {
+		s.Push(v)
+	}
+	for b.Loop() {
+		for range s.elements {
+		}
+	}
+}
+
+// benchStackContains looks up the top element, the worst case for a
+// bottom-up scan.
+func benchStackContains[T comparable](b *testing.B, values []T) {
+	s := NewStack[T]()
+	for _, v := range values {
+		s.Push(v)
+	}
+	target := values[len(values)-1]
+	for b.Loop() {
+		s.ContainsFunc(func(v T) bool { return v == target })
+	}
+}
+
+func BenchmarkStackPushPop(b *testing.B) {
+	runBench(b, benchStackPushPop[int], benchStackPushPop[string])
+}
+
+func BenchmarkStackIterate(b *testing.B) {
+	runBench(b, benchStackIterate[int], benchStackIterate[string])
+}
+
+func BenchmarkStackContains(b *testing.B) {
+	runBench(b, benchStackContains[int], benchStackContains[string])
+}
@@ -0,0 +1,263 @@
+package gocontainers
+
+import (
+	"fmt"
+	"time"
+)
+
+// WindowQueue is a FIFO queue over a sliding window of samples that answers
+// Min, Max and a user-supplied associative fold over the whole window in
+// amortized O(1). It is a two-stack queue: new samples are pushed on a back
+// stack, and samples are moved to a front stack in bulk when the front runs
+// dry. Each stack entry carries the aggregates of the entries beneath it.
+//
+// Old samples can be evicted by count (SetMaxLen), by age (SetMaxAge), or
+// explicitly with EvictBefore.
+type WindowQueue[T any] struct {
+	back       []windowFrame[T] // newest on top, aggregates cover bottom..top
+	front      []windowFrame[T] // oldest on top, aggregates cover top..bottom
+	comparator func(a, b T) bool
+	fold       func(acc, element T) T
+	maxLen     int
+	maxAge     time.Duration
+}
+
+type windowFrame[T any] struct {
+	element T
+	at      time.Time
+	min     T
+	max     T
+	agg     T
+}
+
+// NewWindowQueue creates a new WindowQueue ordered by comparator, which
+// takes the same form as NewHeap's: it returns true if element a comes
+// before element b. Min is then the sample a Heap with the same comparator
+// would pop first, so a min-heap comparator such as cmp.Less gives the
+// smallest sample.
+func NewWindowQueue[T any](comparator func(a, b T) bool) *WindowQueue[T] {
+	return NewWindowQueueWithFold(comparator, nil)
+}
+
+// NewWindowQueueWithFold creates a new WindowQueue ordered by comparator,
+// as for NewWindowQueue, that also maintains fold over its elements, from
+// oldest to newest. fold must be associative.
+func NewWindowQueueWithFold[T any](comparator func(a, b T) bool, fold func(acc, element T) T) *WindowQueue[T] {
+	return &WindowQueue[T]{comparator: comparator, fold: fold}
+}
+
+// SetMaxLen limits the window to the n most recent samples. Older samples
+// are evicted immediately and on every Enqueue. n <= 0 removes the limit.
+func (q *WindowQueue[T]) SetMaxLen(n int) {
+	q.maxLen = n
+	q.evictOverflow()
+}
+
+// SetMaxAge limits the window to samples no older than d relative to the
+// newest sample. The limit is applied on every Enqueue. d <= 0 removes it.
+func (q *WindowQueue[T]) SetMaxAge(d time.Duration) {
+	q.maxAge = d
+}
+
+// Enqueue adds element to the window, timestamped with the current time.
+func (q *WindowQueue[T]) Enqueue(element T) {
+	q.EnqueueAt(element, time.Now())
+}
+
+// EnqueueAt adds element to the window with timestamp at, then applies the
+// count and age limits. Timestamps should not decrease from one call to the
+// next.
+func (q *WindowQueue[T]) EnqueueAt(element T, at time.Time) {
+	frame := windowFrame[T]{element: element, at: at, min: element, max: element, agg: element}
+	if n := len(q.back); n > 0 {
+		below := q.back[n-1]
+		q.combine(&frame, below)
+		if q.fold != nil {
+			frame.agg = q.fold(below.agg, element)
+		}
+	}
+	q.back = append(q.back, frame)
+
+	q.evictOverflow()
+	if q.maxAge > 0 {
+		q.EvictBefore(at.Add(-q.maxAge))
+	}
+}
+
+// combine folds below's min and max into frame.
+func (q *WindowQueue[T]) combine(frame *windowFrame[T], below windowFrame[T]) {
+	if q.comparator(below.min, frame.min) {
+		frame.min = below.min
+	}
+	if q.comparator(frame.max, below.max) {
+		frame.max = below.max
+	}
+}
+
+// Dequeue removes and returns the oldest sample in the window.
+// It panics if the window is empty.
+func (q *WindowQueue[T]) Dequeue() T {
+	q.shift("Dequeue")
+	lastIndex := len(q.front) - 1
+	element := q.front[lastIndex].element
+	q.front = q.front[:lastIndex]
+	return element
+}
+
+// Peek returns the oldest sample in the window without removing it.
+// It panics if the window is empty.
+func (q *WindowQueue[T]) Peek() T {
+	q.shift("Peek")
+	return q.front[len(q.front)-1].element
+}
+
+// shift moves the back stack onto the front stack if the front is empty.
+func (q *WindowQueue[T]) shift(op string) {
+	if len(q.front) > 0 {
+		return
+	}
+	if len(q.back) == 0 {
+		panic(op + " from empty queue")
+	}
+	for i := len(q.back) - 1; i >= 0; i-- {
+		src := q.back[i]
+		frame := windowFrame[T]{element: src.element, at: src.at, min: src.element, max: src.element, agg: src.element}
+		if n := len(q.front); n > 0 {
+			below := q.front[n-1]
+			q.combine(&frame, below)
+			if q.fold != nil {
+				frame.agg = q.fold(src.element, below.agg)
+			}
+		}
+		q.front = append(q.front, frame)
+	}
+	q.back = q.back[:0]
+}
+
+// EvictBefore removes every sample timestamped before t and returns how
+// many were removed.
+func (q *WindowQueue[T]) EvictBefore(t time.Time) int {
+	evicted := 0
+	for q.Size() > 0 {
+		q.shift("EvictBefore")
+		if !q.front[len(q.front)-1].at.Before(t) {
+			break
+		}
+		q.Dequeue()
+		evicted++
+	}
+	return evicted
+}
+
+func (q *WindowQueue[T]) evictOverflow() {
+	for q.maxLen > 0 && q.Size() > q.maxLen {
+		q.Dequeue()
+	}
+}
+
+// Min returns the sample comparator puts first.
+// It panics if the window is empty.
+func (q *WindowQueue[T]) Min() T {
+	f, b := q.tops("Min")
+	switch {
+	case f == nil:
+		return b.min
+	case b == nil:
+		return f.min
+	case q.comparator(b.min, f.min):
+		return b.min
+	}
+	return f.min
+}
+
+// Max returns the sample comparator puts last.
+// It panics if the window is empty.
+func (q *WindowQueue[T]) Max() T {
+	f, b := q.tops("Max")
+	switch {
+	case f == nil:
+		return b.max
+	case b == nil:
+		return f.max
+	case q.comparator(f.max, b.max):
+		return b.max
+	}
+	return f.max
+}
+
+// Aggregate returns the fold over the window from oldest to newest sample.
+// It panics if the window is empty or was created without a fold.
+func (q *WindowQueue[T]) Aggregate() T {
+	if q.fold == nil {
+		panic("Aggregate on queue without fold")
+	}
+	f, b := q.tops("Aggregate")
+	switch {
+	case f == nil:
+		return b.agg
+	case b == nil:
+		return f.agg
+	}
+	return q.fold(f.agg, b.agg)
+}
+
+// tops returns the top frame of each stack, or nil for an empty stack.
+func (q *WindowQueue[T]) tops(op string) (front, back *windowFrame[T]) {
+	if n := len(q.front); n > 0 {
+		front = &q.front[n-1]
+	}
+	if n := len(q.back); n > 0 {
+		back = &q.back[n-1]
+	}
+	if front == nil && back == nil {
+		panic(op + " from empty queue")
+	}
+	return front, back
+}
+
+func (q *WindowQueue[T]) Size() int {
+	return len(q.front) + len(q.back)
+}
+
+func (q *WindowQueue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+func (q *WindowQueue[T]) Clear() {
+	q.front = nil
+	q.back = nil
+}
+
+// ToSlice returns the samples from oldest to newest.
+func (q *WindowQueue[T]) ToSlice() []T {
+	result := make([]T, 0, q.Size())
+	for i := len(q.front) - 1; i >= 0; i-- {
+		result = append(result, q.front[i].element)
+	}
+	for _, frame := range q.back {
+		result = append(result, frame.element)
+	}
+	return result
+}
+
+// CheckInvariants verifies that each frame's Min and Max agree with the
+// frames beneath it on its stack and that the count limit holds. The fold
+// is not checked, since T need not be comparable. It is meant for tests.
+func (q *WindowQueue[T]) CheckInvariants() error {
+	if q.maxLen > 0 && q.Size() > q.maxLen {
+		return fmt.Errorf("WindowQueue: size %d exceeds the limit of %d", q.Size(), q.maxLen)
+	}
+	equiv := func(a, b T) bool { return !q.comparator(a, b) && !q.comparator(b, a) }
+	for name, stack := range map[string][]windowFrame[T]{"back": q.back, "front": q.front} {
+		for i, frame := range stack {
+			want := windowFrame[T]{min: frame.element, max: frame.element}
+			if i > 0 {
+				q.combine(&want, stack[i-1])
+			}
+			if !equiv(frame.min, want.min) || !equiv(frame.max, want.max) {
+				return fmt.Errorf("WindowQueue: %s frame %d min or max disagrees with the frames below", name, i)
+			}
+		}
+	}
+	return nil
+}
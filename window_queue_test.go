@@ -0,0 +1,245 @@
+package gocontainers
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowQueueFIFO(t *testing.T) {
+	q := NewWindowQueue(intLess)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	assert.Equal(t, 1, q.Peek())
+	assert.Equal(t, 1, q.Dequeue())
+	q.Enqueue(3)
+	assert.Equal(t, 2, q.Dequeue())
+	assert.Equal(t, 3, q.Dequeue())
+	assert.True(t, q.IsEmpty())
+}
+
+func TestWindowQueueEmpty(t *testing.T) {
+	q := NewWindowQueueWithFold(intLess, func(acc, v int) int { return acc + v })
+	assert.Panics(t, func() { q.Dequeue() })
+	assert.Panics(t, func() { q.Peek() })
+	assert.Panics(t, func() { q.Min() })
+	assert.Panics(t, func() { q.Max() })
+	assert.Panics(t, func() { q.Aggregate() })
+	assert.Equal(t, 0, q.EvictBefore(time.Now()))
+}
+
+func TestWindowQueueWithoutFold(t *testing.T) {
+	q := NewWindowQueue(intLess)
+	q.Enqueue(1)
+	assert.Panics(t, func() { q.Aggregate() })
+}
+
+func TestWindowQueueAggregatesMatchScan(t *testing.T) {
+	// string concatenation is associative but not commutative, so it also
+	// checks that the fold runs oldest to newest across both stacks
+	concat := func(acc, v string) string { return acc + v }
+	q := NewWindowQueueWithFold(func(a, b string) bool { return a < b }, concat)
+	var model []string
+
+	rng := rand.New(rand.NewPCG(7, 11))
+	for i := 0; i < 2000; i++ {
+		if len(model) > 0 && rng.IntN(3) == 0 {
+			assert.Equal(t, model[0], q.Dequeue())
+			model = model[1:]
+		} else {
+			v := string(rune('a' + rng.IntN(26)))
+			q.Enqueue(v)
+			model = append(model, v)
+		}
+		if len(model) == 0 {
+			continue
+		}
+		assert.Equal(t, len(model), q.Size())
+		assert.Equal(t, slices.Min(model), q.Min())
+		assert.Equal(t, slices.Max(model), q.Max())
+		want := ""
+		for _, v := range model {
+			want += v
+		}
+		assert.Equal(t, want, q.Aggregate())
+	}
+}
+
+func TestWindowQueueMaxLen(t *testing.T) {
+	q := NewWindowQueueWithFold(intLess, func(acc, v int) int { return acc + v })
+	for _, v := range []int{9, 1, 5, 7} {
+		q.Enqueue(v)
+	}
+	q.SetMaxLen(3)
+	assert.Equal(t, 3, q.Size())
+	assert.Equal(t, 1, q.Min())
+	assert.Equal(t, 7, q.Max())
+
+	q.Enqueue(6) // evicts 1
+	q.Enqueue(8) // evicts 5
+	assert.Equal(t, 3, q.Size())
+	assert.Equal(t, 6, q.Min())
+	assert.Equal(t, 8, q.Max())
+	assert.Equal(t, 21, q.Aggregate())
+	assert.Equal(t, 7, q.Peek())
+}
+
+func TestWindowQueueMaxAge(t *testing.T) {
+	q := NewWindowQueue(intLess)
+	q.SetMaxAge(10 * time.Second)
+	start := time.Unix(1000, 0)
+
+	q.EnqueueAt(5, start)
+	q.EnqueueAt(1, start.Add(4*time.Second))
+	q.EnqueueAt(9, start.Add(8*time.Second))
+	assert.Equal(t, 3, q.Size())
+	assert.Equal(t, 1, q.Min())
+
+	q.EnqueueAt(3, start.Add(12*time.Second)) // evicts the sample at 0s
+	assert.Equal(t, 3, q.Size())
+	assert.Equal(t, 1, q.Peek())
+
+	q.EnqueueAt(4, start.Add(15*time.Second)) // evicts the sample at 4s
+	assert.Equal(t, 3, q.Min())
+	assert.Equal(t, 9, q.Max())
+
+	assert.Equal(t, 2, q.EvictBefore(start.Add(13*time.Second)))
+	assert.Equal(t, 4, q.Min())
+	assert.Equal(t, 1, q.Size())
+}
+
+func TestWindowQueueClear(t *testing.T) {
+	q := NewWindowQueue(intLess)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	q.Dequeue()
+	q.Enqueue(3)
+	q.Clear()
+	assert.True(t, q.IsEmpty())
+	assert.Equal(t, 0, q.Size())
+}
+
+func TestWindowQueueHeapComparator(t *testing.T) {
+	// a max-heap comparator puts the largest sample first
+	higher := func(a, b int) bool { return a > b }
+	h := NewHeap(higher)
+	q := NewWindowQueue(higher)
+	for _, v := range []int{3, 1, 4, 1, 5} {
+		h.PushItem(NewItem(v))
+		q.Enqueue(v)
+	}
+	top, _ := h.Peek()
+	assert.Equal(t, top.Get(), q.Min())
+	assert.Equal(t, 1, q.Max())
+}
+
+func FuzzWindowQueue(f *testing.F) {
+	f.Add([]byte{0, 9, 17, 2, 3, 26, 4, 5})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		q := NewWindowQueueWithFold(intLess, func(acc, v int) int { return acc + v })
+		start := time.Unix(0, 0)
+		type sample struct {
+			v  int
+			at time.Time
+		}
+		var model []sample
+		maxLen := 0
+		now := start
+		for i, op := range ops {
+			v := int(op>>3) - 16
+			switch op % 6 {
+			case 0, 1:
+				now = now.Add(time.Duration(op>>5) * time.Second)
+				q.EnqueueAt(v, now)
+				model = append(model, sample{v, now})
+				if maxLen > 0 && len(model) > maxLen {
+					model = model[len(model)-maxLen:]
+				}
+			case 2:
+				if len(model) > 0 {
+					want := model[0].v
+					model = model[1:]
+					if got := q.Dequeue(); got != want {
+						t.Fatalf("op %d: Dequeue = %d, want %d", i, got, want)
+					}
+				}
+			case 3:
+				cutoff := now.Add(-time.Duration(op>>4) * time.Second)
+				n := 0
+				for n < len(model) && model[n].at.Before(cutoff) {
+					n++
+				}
+				model = model[n:]
+				if got := q.EvictBefore(cutoff); got != n {
+					t.Fatalf("op %d: EvictBefore = %d, want %d", i, got, n)
+				}
+			case 4:
+				maxLen = int(op>>3) % 8
+				q.SetMaxLen(maxLen)
+				if maxLen > 0 && len(model) > maxLen {
+					model = model[len(model)-maxLen:]
+				}
+			case 5:
+				if v%4 == 0 {
+					q.Clear()
+					model = nil
+				}
+			}
+
+			if err := q.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), q.Size())
+			if len(model) > 0 {
+				lo, hi, sum := model[0].v, model[0].v, 0
+				for _, s := range model {
+					lo, hi, sum = min(lo, s.v), max(hi, s.v), sum+s.v
+				}
+				assert.Equal(t, model[0].v, q.Peek(), "after op %d (%d)", i, op)
+				assert.Equal(t, lo, q.Min(), "after op %d (%d)", i, op)
+				assert.Equal(t, hi, q.Max(), "after op %d (%d)", i, op)
+				assert.Equal(t, sum, q.Aggregate(), "after op %d (%d)", i, op)
+			}
+		}
+	})
+}
+
+// benchWindowQueueEnqueueDequeue uses one fixed timestamp so the clock is
+// not part of the measurement.
+func benchWindowQueueEnqueueDequeue[T cmp.Ordered](b *testing.B, values []T) {
+	at := time.Unix(0, 0)
+	for b.Loop() {
+		q := NewWindowQueue(cmp.Less[T])
+		for _, v := range values {
+			q.EnqueueAt(v, at)
+		}
+		for !q.IsEmpty() {
+			q.Dequeue()
+		}
+	}
+}
+
+func benchWindowQueueIterate[T cmp.Ordered](b *testing.B, values []T) {
+	q := NewWindowQueue(cmp.Less[T])
+	for _, v := range values {
+		q.EnqueueAt(v, time.Unix(0, 0))
+	}
+	for b.Loop() {
+		for range q.front {
+		}
+		for range q.back {
+		}
+	}
+}
+
+func BenchmarkWindowQueueEnqueueDequeue(b *testing.B) {
+	runBench(b, benchWindowQueueEnqueueDequeue[int], benchWindowQueueEnqueueDequeue[string])
+}
+
+func BenchmarkWindowQueueIterate(b *testing.B) {
+	runBench(b, benchWindowQueueIterate[int], benchWindowQueueIterate[string])
+}
@@ -0,0 +1,163 @@
+package gocontainers
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// WorkStealingDeque is a Chase-Lev work-stealing deque for fork-join style
+// schedulers. A single owner goroutine pushes and pops at the bottom, while
+// any number of thief goroutines steal from the top. The owner operations
+// never retry and Steal is lock-free, so no goroutine ever blocks.
+//
+// PushBottom and PopBottom must only be called by the owner. Steal may be
+// called from any goroutine. The buffer doubles as needed and never shrinks.
+type WorkStealingDeque[T any] struct {
+	top     atomic.Int64
+	bottom  atomic.Int64
+	buf     atomic.Pointer[wsBuffer[T]]
+	cleared int64 // owner only: stolen slots below this index are zeroed
+}
+
+// wsBuffer is a circular array of element pointers. Slots are atomic so
+// thieves can read them while the owner writes other slots.
+type wsBuffer[T any] struct {
+	slots []atomic.Pointer[T]
+	mask  int64
+}
+
+const wsInitialCapacity = 32
+
+func newWSBuffer[T any](capacity int64) *wsBuffer[T] {
+	return &wsBuffer[T]{slots: make([]atomic.Pointer[T], capacity), mask: capacity - 1}
+}
+
+func (b *wsBuffer[T]) get(i int64) *T {
+	return b.slots[i&b.mask].Load()
+}
+
+func (b *wsBuffer[T]) put(i int64, v *T) {
+	b.slots[i&b.mask].Store(v)
+}
+
+// grow returns a buffer twice the size holding the elements in [top, bottom).
+// The old buffer is left intact for thieves that still hold it.
+func (b *wsBuffer[T]) grow(bottom, top int64) *wsBuffer[T] {
+	nb := newWSBuffer[T](2 * int64(len(b.slots)))
+	for i := top; i < bottom; i++ {
+		nb.put(i, b.get(i))
+	}
+	return nb
+}
+
+func NewWorkStealingDeque[T any]() *WorkStealingDeque[T] {
+	d := &WorkStealingDeque[T]{}
+	d.buf.Store(newWSBuffer[T](wsInitialCapacity))
+	return d
+}
+
+// PushBottom adds element at the bottom of the deque. Owner only.
+func (d *WorkStealingDeque[T]) PushBottom(element T) {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	buf := d.buf.Load()
+	n := int64(len(buf.slots))
+	if b-t >= n {
+		buf = buf.grow(b, t)
+		d.buf.Store(buf)
+		n *= 2
+		d.cleared = t
+	}
+	// Release the elements thieves took since the last push. Their CAS has
+	// moved top past these slots, so no thief will read them again. Slots
+	// that wrap onto [t, b] are left alone: they are live or about to be
+	// overwritten.
+	for i := max(d.cleared, b+1-n); i < t; i++ {
+		buf.put(i, nil)
+	}
+	d.cleared = max(d.cleared, t)
+	buf.put(b, &element)
+	d.bottom.Store(b + 1)
+}
+
+// PopBottom removes and returns the element at the bottom of the deque,
+// i.e. the one most recently pushed. It returns false if the deque is empty
+// or the last element was stolen concurrently. Owner only.
+func (d *WorkStealingDeque[T]) PopBottom() (T, bool) {
+	var zero T
+	b := d.bottom.Load() - 1
+	buf := d.buf.Load()
+	d.bottom.Store(b)
+	t := d.top.Load()
+	if t > b {
+		d.bottom.Store(b + 1)
+		return zero, false
+	}
+
+	x := buf.get(b)
+	if t == b {
+		// last element: race thieves for it by advancing top
+		won := d.top.CompareAndSwap(t, t+1)
+		d.bottom.Store(b + 1)
+		if !won {
+			return zero, false // the thief's slot is zeroed by a later push
+		}
+	}
+	buf.put(b, nil) // don't keep the popped element reachable
+	return *x, true
+}
+
+// Steal removes and returns the element at the top of the deque, i.e. the
+// oldest one. It returns false if the deque is empty. Safe to call from
+// any goroutine.
+func (d *WorkStealingDeque[T]) Steal() (T, bool) {
+	for {
+		t := d.top.Load()
+		b := d.bottom.Load()
+		if t >= b {
+			var zero T
+			return zero, false
+		}
+		x := d.buf.Load().get(t)
+		if d.top.CompareAndSwap(t, t+1) {
+			return *x, true
+		}
+		// lost the race to another thief or the owner, try again
+	}
+}
+
+// Size returns the number of elements in the deque. Under concurrent use
+// the result is approximate.
+func (d *WorkStealingDeque[T]) Size() int {
+	n := d.bottom.Load() - d.top.Load()
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+// IsEmpty reports whether the deque has no elements at the time of the call.
+func (d *WorkStealingDeque[T]) IsEmpty() bool {
+	return d.Size() == 0
+}
+
+// CheckInvariants verifies that top does not pass bottom, that the buffer
+// is a power of two large enough for the elements, and that every slot
+// between top and bottom is filled. It is meant for tests and must not run
+// concurrently with other operations.
+func (d *WorkStealingDeque[T]) CheckInvariants() error {
+	t, b, buf := d.top.Load(), d.bottom.Load(), d.buf.Load()
+	n := int64(len(buf.slots))
+	if n == 0 || n&(n-1) != 0 || buf.mask != n-1 {
+		return fmt.Errorf("WorkStealingDeque: buffer of %d slots with mask %d", n, buf.mask)
+	}
+	if t > b || b-t > n {
+		return fmt.Errorf("WorkStealingDeque: top %d and bottom %d in a buffer of %d slots", t, b, n)
+	}
+	for i := t; i < b; i++ {
+		if buf.get(i) == nil {
+			return fmt.Errorf("WorkStealingDeque: slot %d is empty", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package gocontainers
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkStealingDequeOwner(t *testing.T) {
+	d := NewWorkStealingDeque[int]()
+	assert.True(t, d.IsEmpty())
+
+	_, ok := d.PopBottom()
+	assert.False(t, ok)
+
+	d.PushBottom(1)
+	d.PushBottom(2)
+	d.PushBottom(3)
+	assert.Equal(t, 3, d.Size())
+
+	v, ok := d.PopBottom()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	v, ok = d.Steal()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	v, ok = d.PopBottom()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	_, ok = d.PopBottom()
+	assert.False(t, ok)
+	_, ok = d.Steal()
+	assert.False(t, ok)
+	assert.Equal(t, 0, d.Size())
+}
+
+func TestWorkStealingDequeGrow(t *testing.T) {
+	d := NewWorkStealingDeque[int]()
+	const n = wsInitialCapacity*4 + 3
+
+	// interleave steals so top moves and the buffer wraps before growing
+	for i := 0; i < n; i++ {
+		d.PushBottom(i)
+		if i%3 == 0 {
+			d.PushBottom(-1)
+			v, _ := d.PopBottom()
+			assert.Equal(t, -1, v)
+		}
+	}
+	assert.Equal(t, n, d.Size())
+
+	for i := 0; i < n/2; i++ {
+		v, ok := d.Steal()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	for i := n - 1; i >= n/2; i-- {
+		v, ok := d.PopBottom()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, d.IsEmpty())
+}
+
+func TestWorkStealingDequeReleasesSlots(t *testing.T) {
+	d := NewWorkStealingDeque[int]()
+	for i := range 4 {
+		d.PushBottom(i)
+	}
+	buf := d.buf.Load()
+
+	v, ok := d.PopBottom()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Nil(t, buf.get(3), "popped slot is zeroed")
+
+	d.Steal()
+	d.Steal()
+	assert.NotNil(t, buf.get(0), "stolen slots are zeroed by the owner, not the thief")
+	d.PushBottom(4)
+	assert.Nil(t, buf.get(0))
+	assert.Nil(t, buf.get(1))
+	assert.NoError(t, d.CheckInvariants())
+
+	// the last element, taken by PopBottom after racing for top
+	d.Steal()
+	v, ok = d.PopBottom()
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	assert.Nil(t, buf.get(3))
+	d.PushBottom(5)
+	for i := range int64(4) {
+		assert.Nil(t, buf.get(i), "slot %d", i)
+	}
+}
+
+func TestWorkStealingDequeStress(t *testing.T) {
+	const thieves, items = 6, 50000
+	d := NewWorkStealingDeque[int]()
+
+	var taken [items]atomic.Int32
+	var count atomic.Int64
+	var stop atomic.Bool
+
+	var wg sync.WaitGroup
+	for i := 0; i < thieves; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				if v, ok := d.Steal(); ok {
+					taken[v].Add(1)
+					count.Add(1)
+				}
+			}
+		}()
+	}
+
+	// owner pushes everything while popping some of its own work back
+	for i := 0; i < items; i++ {
+		d.PushBottom(i)
+		if i%4 == 0 {
+			if v, ok := d.PopBottom(); ok {
+				taken[v].Add(1)
+				count.Add(1)
+			}
+		}
+	}
+	for {
+		v, ok := d.PopBottom()
+		if !ok {
+			break
+		}
+		taken[v].Add(1)
+		count.Add(1)
+	}
+	for count.Load() < items {
+		runtime.Gosched()
+	}
+	stop.Store(true)
+	wg.Wait()
+
+	for i := range taken {
+		if n := taken[i].Load(); n != 1 {
+			t.Fatalf("item %d taken %d times", i, n)
+		}
+	}
+}
+
+// sumRange is a fork-join task: it sums [lo, hi) by splitting large ranges
+// into subtasks.
+type sumRange struct{ lo, hi int }
+
+// ExampleWorkStealingDeque runs a small fork-join worker pool. Each worker
+// owns a deque, splits large tasks onto it, and steals from the other
+// workers when its own deque runs dry.
+func ExampleWorkStealingDeque() {
+	const workers = 4
+	deques := make([]*WorkStealingDeque[sumRange], workers)
+	for i := range deques {
+		deques[i] = NewWorkStealingDeque[sumRange]()
+	}
+
+	var total, pending atomic.Int64
+	pending.Add(1)
+	deques[0].PushBottom(sumRange{lo: 1, hi: 10001})
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			own := deques[w]
+			for pending.Load() > 0 {
+				task, ok := own.PopBottom()
+				for i := 1; !ok && i < workers; i++ {
+					task, ok = deques[(w+i)%workers].Steal()
+				}
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+
+				for task.hi-task.lo > 64 {
+					mid := (task.lo + task.hi) / 2
+					pending.Add(1)
+					own.PushBottom(sumRange{lo: mid, hi: task.hi})
+					task.hi = mid
+				}
+				sum := 0
+				for i := task.lo; i < task.hi; i++ {
+					sum += i
+				}
+				total.Add(int64(sum))
+				pending.Add(-1)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	fmt.Println(total.Load())
+	// Output: 50005000
+}
+
+func TestWorkStealingDequeCheckInvariantsDetectsCorruption(t *testing.T) {
+	d := NewWorkStealingDeque[int]()
+	d.PushBottom(1)
+	d.PushBottom(2)
+	assert.NoError(t, d.CheckInvariants())
+
+	d.top.Store(3)
+	assert.Error(t, d.CheckInvariants())
+	d.top.Store(0)
+
+	d.buf.Load().put(1, nil)
+	assert.Error(t, d.CheckInvariants())
+}
+
+func FuzzWorkStealingDeque(f *testing.F) {
+	f.Add([]byte{0, 4, 8, 1, 2, 5, 6})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		d := NewWorkStealingDeque[int]()
+		var model []int // bottom of the deque last
+		for i, op := range ops {
+			v := int(op >> 2)
+			switch op % 4 {
+			case 0, 3:
+				d.PushBottom(v)
+				model = append(model, v)
+			case 1:
+				got, ok := d.PopBottom()
+				if ok != (len(model) > 0) {
+					t.Fatalf("op %d: PopBottom ok = %t", i, ok)
+				}
+				if ok {
+					if want := model[len(model)-1]; got != want {
+						t.Fatalf("op %d: PopBottom = %d, want %d", i, got, want)
+					}
+					model = model[:len(model)-1]
+				}
+			case 2:
+				got, ok := d.Steal()
+				if ok != (len(model) > 0) {
+					t.Fatalf("op %d: Steal ok = %t", i, ok)
+				}
+				if ok {
+					if got != model[0] {
+						t.Fatalf("op %d: Steal = %d, want %d", i, got, model[0])
+					}
+					model = model[1:]
+				}
+			}
+
+			if err := d.CheckInvariants(); err != nil {
+				t.Fatalf("after op %d (%d): %v", i, op, err)
+			}
+			assert.Equal(t, len(model), d.Size())
+			assert.Equal(t, len(model) == 0, d.IsEmpty())
+		}
+	})
+}
+
+// benchWorkStealingDequePushPop measures the owner's side alone, with no
+// thieves.
+func benchWorkStealingDequePushPop[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		d := NewWorkStealingDeque[T]()
+		for _, v := range values {
+			d.PushBottom(v)
+		}
+		for {
+			if _, ok := d.PopBottom(); !ok {
+				break
+			}
+		}
+	}
+}
+
+func benchWorkStealingDequeSteal[T any](b *testing.B, values []T) {
+	for b.Loop() {
+		d := NewWorkStealingDeque[T]()
+		for _, v := range values {
+			d.PushBottom(v)
+		}
+		for {
+			if _, ok := d.Steal(); !ok {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkWorkStealingDequePushPop(b *testing.B) {
+	runBench(b, benchWorkStealingDequePushPop[int], benchWorkStealingDequePushPop[string])
+}
+
+func BenchmarkWorkStealingDequeSteal(b *testing.B) {
+	runBench(b, benchWorkStealingDequeSteal[int], benchWorkStealingDequeSteal[string])
+}